@@ -7,12 +7,14 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/youtube-service/internal/router"
 	"github.com/youtube-service/internal/db/mongo"
+	"github.com/youtube-service/internal/events"
+	"github.com/youtube-service/internal/grpcapi"
 	"github.com/youtube-service/internal/handlers"
 	"github.com/youtube-service/internal/models-services"
 	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/pubsub"
 	"github.com/youtube-service/pkg/logger"
 	log "github.com/sirupsen/logrus"
-	"github.com/go-playground/validator/v10"
 )
 
 func main() {
@@ -26,6 +28,35 @@ func main() {
 	config.InitConfig()
 	db.ConnectionDb()
 
+	// Enqueue the deliveries of newly ingested videos to the matching webhooks
+	pubsub.Handle(webhooks.EnqueueDeliveries)
+	// Alert the rules watching for newly ingested videos
+	pubsub.Handle(alerts.EvaluateRules)
+
+	publisher, err := events.NewPublisher()
+	if err != nil {
+		log.Fatalf("main: failed to connect to the events broker: %v", err)
+	}
+	if publisher != nil {
+		defer publisher.Close()
+		// Store the events of newly ingested videos in the outbox and publish them periodically
+		outbox.Enable()
+		go func() {
+			ticker := time.NewTicker(time.Duration(configs.GetEventsRelaySeconds()) * time.Second)
+			quit := make(chan struct{})
+			for {
+				select {
+				case <-ticker.C:
+					outbox.Relay(publisher)
+				case <-quit:
+					ticker.Stop()
+					return
+				}
+			}
+
+		}()
+	}
+
 	// Start a goroutine to fetch videos from youtube periodically
 	go func() {
 		ticker := time.NewTicker(time.Duration(config.GetFetchLatestVideosSeconds()) * time.Second)
@@ -45,6 +76,29 @@ func main() {
 
 	}()
 
+	// Start a goroutine to snapshot video statistics and compute trending videos periodically
+	go func() {
+		ticker := time.NewTicker(time.Duration(configs.GetStatsSnapshotMinutes()) * time.Minute)
+		quit := make(chan struct{})
+		for {
+			select {
+			case <-ticker.C:
+				err := get_video-search_video.SnapshotVideoStats()
+				if err != nil {
+					log.Errorf("main: error snapshotting video statistics: %v", err)
+				}
+				err = get_video-search_video.ComputeTrending()
+				if err != nil {
+					log.Errorf("main: error computing trending videos: %v", err)
+				}
+			case <-quit:
+				ticker.Stop()
+				return
+			}
+		}
+
+	}()
+
 	// Start a goroutine to update expiation of API keys in the database periodically
 	go func() {
 		ticker := time.NewTicker(time.Duration(config.GetUpdateApiKeysExpirationMinutes()) * time.Minute)
@@ -61,7 +115,48 @@ func main() {
 
 	}()
 
-	app := fiber.New()
+	// Start a goroutine to deliver the pending webhook deliveries periodically
+	go func() {
+		ticker := time.NewTicker(time.Duration(configs.GetWebhookDeliverySeconds()) * time.Second)
+		quit := make(chan struct{})
+		for {
+			select {
+			case <-ticker.C:
+				webhooks.DeliverPending()
+			case <-quit:
+				ticker.Stop()
+				return
+			}
+		}
+
+	}()
+
+	// Start a goroutine to send the alerts which failed again periodically
+	go func() {
+		ticker := time.NewTicker(time.Duration(configs.GetAlertRetrySeconds()) * time.Second)
+		quit := make(chan struct{})
+		for {
+			select {
+			case <-ticker.C:
+				alerts.RetryPending()
+			case <-quit:
+				ticker.Stop()
+				return
+			}
+		}
+
+	}()
+
+	// Serve the gRPC API alongside the HTTP one
+	go func() {
+		if err := grpcapi.Serve(configs.GetGrpcPort()); err != nil {
+			log.Fatalf("main: error serving gRPC API: %v", err)
+		}
+	}()
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handlers.ErrorHandler,
+	})
 	router.SetRoutes(app)
 
 	log.Infof("main: Starting server on port %v", config.GetPort())
@@ -3,6 +3,7 @@ package configs
 import (
 	"flag"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
@@ -10,10 +11,41 @@ import (
 )
 
 type Config struct {
-	Port                       string
+	Port                           string
+	GrpcPort                       string
 	Etag                           string
 	MaxVideosFetched               int64
 	PerPageLimit                   int64
+	MaxPerPageLimit                int64
+	RecencyHalfLifeHours           int64
+	TitleWeight                    int64
+	DescriptionWeight              int64
+	TagsWeight                     int64
+	MinSearchScore                 float64
+	SuggestMaxPrefixLength         int64
+	FuzzyFallbackThreshold         int64
+	HighlightPreTag                string
+	HighlightPostTag               string
+	SnippetLength                  int64
+	MaxBatchGetIds                 int64
+	StatsSnapshotMinutes           int64
+	TrendingWindowHours            int64
+	TrendingMaxTrackedVideos       int64
+	TrendingLikeWeight             float64
+	AdminToken                     string
+	WebhookDeliverySeconds         int64
+	WebhookMaxAttempts             int64
+	AlertRetrySeconds              int64
+	SmtpHost                       string
+	SmtpPort                       int64
+	SmtpUsername                   string
+	SmtpPassword                   string
+	SmtpFrom                       string
+	EventsBroker                   string
+	NatsUrl                        string
+	KafkaBrokers                   []string
+	EventsTopic                    string
+	EventsRelaySeconds             int64
 	FetchLatestVideosSeconds       int64
 	UpdateApiKeysExpirationMinutes int64
 	Query                          string
@@ -22,8 +54,31 @@ type Config struct {
 }
 
 const (
+	DEFAULT_GRPC_PORT                          = ":3501"
 	DEFAULT_MAX_TOKENS                         = 5
 	DEFAULT_PER_PAGE_LIMIT                     = 5
+	DEFAULT_MAX_PER_PAGE_LIMIT                 = 50
+	DEFAULT_RECENCY_HALF_LIFE_HOURS            = 0
+	DEFAULT_TEXT_INDEX_WEIGHT                  = 1
+	DEFAULT_MIN_SEARCH_SCORE                   = 1
+	DEFAULT_SUGGEST_MAX_PREFIX_LENGTH          = 20
+	DEFAULT_FUZZY_FALLBACK_THRESHOLD           = 3
+	DEFAULT_HIGHLIGHT_PRE_TAG                  = "<em>"
+	DEFAULT_HIGHLIGHT_POST_TAG                 = "</em>"
+	DEFAULT_SNIPPET_LENGTH                     = 160
+	DEFAULT_MAX_BATCH_GET_IDS                  = 100
+	DEFAULT_STATS_SNAPSHOT_MINUTES             = 60
+	DEFAULT_TRENDING_WINDOW_HOURS              = 24
+	DEFAULT_TRENDING_MAX_TRACKED_VIDEOS        = 200
+	DEFAULT_TRENDING_LIKE_WEIGHT               = 10
+	DEFAULT_WEBHOOK_DELIVERY_SECONDS           = 5
+	DEFAULT_WEBHOOK_MAX_ATTEMPTS               = 8
+	DEFAULT_ALERT_RETRY_SECONDS                = 30
+	DEFAULT_SMTP_PORT                          = 587
+	DEFAULT_NATS_URL                           = "nats://127.0.0.1:4222"
+	DEFAULT_KAFKA_BROKERS                      = "127.0.0.1:9092"
+	DEFAULT_EVENTS_TOPIC                       = "youtube.videos.ingested"
+	DEFAULT_EVENTS_RELAY_SECONDS               = 5
 	DEFAULT_FETCH_LATEST_VIDEOS_SECONDS        = 10
 	DEFAULT_UPDATE_API_KEYS_EXPIRATION_MINUTES = 120
 )
@@ -37,6 +92,15 @@ func InitConfig() {
 		log.Fatalf("Config: Environment variable PORT not found. Please refer to README to find how to set it.")
 	}
 
+	flag.StringVar(&configs.GrpcPort, "grpcport", os.Getenv("GRPC_PORT"), "Port where the gRPC server is running")
+	if configs.GrpcPort == "" {
+		log.Infof("Config: Environment variable GRPC_PORT not found. Setting it to default value: %s", DEFAULT_GRPC_PORT)
+		configs.GrpcPort = DEFAULT_GRPC_PORT
+	}
+	if configs.GrpcPort == configs.Port {
+		log.Fatalf("Config: GRPC_PORT should be different from PORT. Please refer to README to find how to set it.")
+	}
+
 	flag.StringVar(&configs.MongoDbURI, "mongodburi", os.Getenv("MONGODB_URI"), "MongoDB URI for connection")
 	if configs.MongoDbURI == "" {
 		log.Fatalf("Config: Environment variable MONGODB_URI not found. Please refer to README to find how to set it.")
@@ -59,6 +123,181 @@ func InitConfig() {
 		configs.PerPageLimit = DEFAULT_PER_PAGE_LIMIT
 	}
 
+	flag.Int64Var(&configs.MaxPerPageLimit, "maxperpagelimit", utils.GetEnvInt("MAX_PER_PAGE_LIMIT", DEFAULT_MAX_PER_PAGE_LIMIT), "Max number of videos a client can request per page")
+	if configs.MaxPerPageLimit < 1 {
+		log.Infof("Config: Environment variable MAX_PER_PAGE_LIMIT should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_MAX_PER_PAGE_LIMIT)
+		configs.MaxPerPageLimit = DEFAULT_MAX_PER_PAGE_LIMIT
+	}
+	if configs.PerPageLimit > configs.MaxPerPageLimit {
+		log.Infof("Config: PER_PAGE_LIMIT should not be greater than MAX_PER_PAGE_LIMIT. Setting it to %d", configs.MaxPerPageLimit)
+		configs.PerPageLimit = configs.MaxPerPageLimit
+	}
+
+	flag.Int64Var(&configs.RecencyHalfLifeHours, "recencyhalflifehours", utils.GetEnvInt("RECENCY_HALF_LIFE_HOURS", DEFAULT_RECENCY_HALF_LIFE_HOURS), "Default half life in hours of the recency decay blended into search relevance, 0 to disable it")
+	if configs.RecencyHalfLifeHours < 0 {
+		log.Infof("Config: Environment variable RECENCY_HALF_LIFE_HOURS should not be negative. Please refer to README. Setting it to default value: %d", DEFAULT_RECENCY_HALF_LIFE_HOURS)
+		configs.RecencyHalfLifeHours = DEFAULT_RECENCY_HALF_LIFE_HOURS
+	}
+
+	flag.Int64Var(&configs.TitleWeight, "titleweight", utils.GetEnvInt("TITLE_WEIGHT", DEFAULT_TEXT_INDEX_WEIGHT), "Weight of the title in the text search score")
+	if configs.TitleWeight < 1 {
+		log.Infof("Config: Environment variable TITLE_WEIGHT should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_TEXT_INDEX_WEIGHT)
+		configs.TitleWeight = DEFAULT_TEXT_INDEX_WEIGHT
+	}
+
+	flag.Int64Var(&configs.DescriptionWeight, "descriptionweight", utils.GetEnvInt("DESCRIPTION_WEIGHT", DEFAULT_TEXT_INDEX_WEIGHT), "Weight of the description in the text search score")
+	if configs.DescriptionWeight < 1 {
+		log.Infof("Config: Environment variable DESCRIPTION_WEIGHT should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_TEXT_INDEX_WEIGHT)
+		configs.DescriptionWeight = DEFAULT_TEXT_INDEX_WEIGHT
+	}
+
+	flag.Int64Var(&configs.TagsWeight, "tagsweight", utils.GetEnvInt("TAGS_WEIGHT", DEFAULT_TEXT_INDEX_WEIGHT), "Weight of the tags in the text search score")
+	if configs.TagsWeight < 1 {
+		log.Infof("Config: Environment variable TAGS_WEIGHT should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_TEXT_INDEX_WEIGHT)
+		configs.TagsWeight = DEFAULT_TEXT_INDEX_WEIGHT
+	}
+
+	flag.Float64Var(&configs.MinSearchScore, "minsearchscore", utils.GetEnvFloat("MIN_SEARCH_SCORE", DEFAULT_MIN_SEARCH_SCORE), "Minimum text score of search results")
+	if configs.MinSearchScore < 0 {
+		log.Infof("Config: Environment variable MIN_SEARCH_SCORE should not be negative. Please refer to README. Setting it to default value: %d", DEFAULT_MIN_SEARCH_SCORE)
+		configs.MinSearchScore = DEFAULT_MIN_SEARCH_SCORE
+	}
+
+	flag.Int64Var(&configs.SuggestMaxPrefixLength, "suggestmaxprefixlength", utils.GetEnvInt("SUGGEST_MAX_PREFIX_LENGTH", DEFAULT_SUGGEST_MAX_PREFIX_LENGTH), "Max length of the prefixes indexed for suggestions")
+	if configs.SuggestMaxPrefixLength < 1 {
+		log.Infof("Config: Environment variable SUGGEST_MAX_PREFIX_LENGTH should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_SUGGEST_MAX_PREFIX_LENGTH)
+		configs.SuggestMaxPrefixLength = DEFAULT_SUGGEST_MAX_PREFIX_LENGTH
+	}
+
+	flag.Int64Var(&configs.FuzzyFallbackThreshold, "fuzzyfallbackthreshold", utils.GetEnvInt("FUZZY_FALLBACK_THRESHOLD", DEFAULT_FUZZY_FALLBACK_THRESHOLD), "Number of search results under which typos of the query are corrected, 0 to disable it")
+	if configs.FuzzyFallbackThreshold < 0 {
+		log.Infof("Config: Environment variable FUZZY_FALLBACK_THRESHOLD should not be negative. Please refer to README. Setting it to default value: %d", DEFAULT_FUZZY_FALLBACK_THRESHOLD)
+		configs.FuzzyFallbackThreshold = DEFAULT_FUZZY_FALLBACK_THRESHOLD
+	}
+
+	flag.StringVar(&configs.HighlightPreTag, "highlightpretag", os.Getenv("HIGHLIGHT_PRE_TAG"), "Marker inserted before matched terms in search highlights")
+	if configs.HighlightPreTag == "" {
+		configs.HighlightPreTag = DEFAULT_HIGHLIGHT_PRE_TAG
+	}
+
+	flag.StringVar(&configs.HighlightPostTag, "highlightposttag", os.Getenv("HIGHLIGHT_POST_TAG"), "Marker inserted after matched terms in search highlights")
+	if configs.HighlightPostTag == "" {
+		configs.HighlightPostTag = DEFAULT_HIGHLIGHT_POST_TAG
+	}
+
+	flag.Int64Var(&configs.SnippetLength, "snippetlength", utils.GetEnvInt("SNIPPET_LENGTH", DEFAULT_SNIPPET_LENGTH), "Max number of characters of the highlighted description snippet of search results")
+	if configs.SnippetLength < 1 {
+		log.Infof("Config: Environment variable SNIPPET_LENGTH should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_SNIPPET_LENGTH)
+		configs.SnippetLength = DEFAULT_SNIPPET_LENGTH
+	}
+
+	flag.Int64Var(&configs.MaxBatchGetIds, "maxbatchgetids", utils.GetEnvInt("MAX_BATCH_GET_IDS", DEFAULT_MAX_BATCH_GET_IDS), "Max number of video ids which can be looked up in one batch get request")
+	if configs.MaxBatchGetIds < 1 {
+		log.Infof("Config: Environment variable MAX_BATCH_GET_IDS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_MAX_BATCH_GET_IDS)
+		configs.MaxBatchGetIds = DEFAULT_MAX_BATCH_GET_IDS
+	}
+
+	flag.Int64Var(&configs.StatsSnapshotMinutes, "statssnapshotminutes", utils.GetEnvInt("STATS_SNAPSHOT_MINUTES", DEFAULT_STATS_SNAPSHOT_MINUTES), "Number of minutes after which statistics of recent videos are snapshotted and trending videos are computed")
+	if configs.StatsSnapshotMinutes < 1 {
+		log.Infof("Config: Environment variable STATS_SNAPSHOT_MINUTES should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_STATS_SNAPSHOT_MINUTES)
+		configs.StatsSnapshotMinutes = DEFAULT_STATS_SNAPSHOT_MINUTES
+	}
+
+	flag.Int64Var(&configs.TrendingWindowHours, "trendingwindowhours", utils.GetEnvInt("TRENDING_WINDOW_HOURS", DEFAULT_TRENDING_WINDOW_HOURS), "Number of hours over which the view and like velocity of trending videos is computed")
+	if configs.TrendingWindowHours < 1 || configs.TrendingWindowHours > 7*24 {
+		log.Infof("Config: Environment variable TRENDING_WINDOW_HOURS should be between 1 and 168. Please refer to README. Setting it to default value: %d", DEFAULT_TRENDING_WINDOW_HOURS)
+		configs.TrendingWindowHours = DEFAULT_TRENDING_WINDOW_HOURS
+	}
+
+	flag.Int64Var(&configs.TrendingMaxTrackedVideos, "trendingmaxtrackedvideos", utils.GetEnvInt("TRENDING_MAX_TRACKED_VIDEOS", DEFAULT_TRENDING_MAX_TRACKED_VIDEOS), "Number of most recently published videos whose statistics are snapshotted")
+	if configs.TrendingMaxTrackedVideos < 1 {
+		log.Infof("Config: Environment variable TRENDING_MAX_TRACKED_VIDEOS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_TRENDING_MAX_TRACKED_VIDEOS)
+		configs.TrendingMaxTrackedVideos = DEFAULT_TRENDING_MAX_TRACKED_VIDEOS
+	}
+
+	flag.Float64Var(&configs.TrendingLikeWeight, "trendinglikeweight", utils.GetEnvFloat("TRENDING_LIKE_WEIGHT", DEFAULT_TRENDING_LIKE_WEIGHT), "Weight of the like velocity relative to the view velocity in the trending score")
+	if configs.TrendingLikeWeight < 0 {
+		log.Infof("Config: Environment variable TRENDING_LIKE_WEIGHT should not be negative. Please refer to README. Setting it to default value: %d", DEFAULT_TRENDING_LIKE_WEIGHT)
+		configs.TrendingLikeWeight = DEFAULT_TRENDING_LIKE_WEIGHT
+	}
+
+	flag.StringVar(&configs.AdminToken, "admintoken", os.Getenv("ADMIN_TOKEN"), "Bearer token of admin requests, e.g. GraphQL key management")
+	if configs.AdminToken == "" {
+		log.Infof("Config: Environment variable ADMIN_TOKEN not set. Admin operations are disabled.")
+	}
+
+	flag.Int64Var(&configs.WebhookDeliverySeconds, "webhookdeliveryseconds", utils.GetEnvInt("WEBHOOK_DELIVERY_SECONDS", DEFAULT_WEBHOOK_DELIVERY_SECONDS), "Number of seconds after which pending webhook deliveries are sent")
+	if configs.WebhookDeliverySeconds < 1 {
+		log.Infof("Config: Environment variable WEBHOOK_DELIVERY_SECONDS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_WEBHOOK_DELIVERY_SECONDS)
+		configs.WebhookDeliverySeconds = DEFAULT_WEBHOOK_DELIVERY_SECONDS
+	}
+
+	flag.Int64Var(&configs.WebhookMaxAttempts, "webhookmaxattempts", utils.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", DEFAULT_WEBHOOK_MAX_ATTEMPTS), "Number of attempts after which failing webhook deliveries are dead lettered")
+	if configs.WebhookMaxAttempts < 1 {
+		log.Infof("Config: Environment variable WEBHOOK_MAX_ATTEMPTS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_WEBHOOK_MAX_ATTEMPTS)
+		configs.WebhookMaxAttempts = DEFAULT_WEBHOOK_MAX_ATTEMPTS
+	}
+
+	flag.Int64Var(&configs.AlertRetrySeconds, "alertretryseconds", utils.GetEnvInt("ALERT_RETRY_SECONDS", DEFAULT_ALERT_RETRY_SECONDS), "Number of seconds after which alerts which failed are sent again")
+	if configs.AlertRetrySeconds < 1 {
+		log.Infof("Config: Environment variable ALERT_RETRY_SECONDS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_ALERT_RETRY_SECONDS)
+		configs.AlertRetrySeconds = DEFAULT_ALERT_RETRY_SECONDS
+	}
+
+	flag.StringVar(&configs.SmtpHost, "smtphost", os.Getenv("SMTP_HOST"), "Host of the SMTP server sending email alerts")
+	if configs.SmtpHost == "" {
+		log.Infof("Config: Environment variable SMTP_HOST not set. Email alerts are disabled.")
+	}
+
+	flag.Int64Var(&configs.SmtpPort, "smtpport", utils.GetEnvInt("SMTP_PORT", DEFAULT_SMTP_PORT), "Port of the SMTP server, 465 for implicit TLS")
+	if configs.SmtpPort < 1 || configs.SmtpPort > 65535 {
+		log.Infof("Config: Environment variable SMTP_PORT should be between 1 and 65535. Please refer to README. Setting it to default value: %d", DEFAULT_SMTP_PORT)
+		configs.SmtpPort = DEFAULT_SMTP_PORT
+	}
+
+	flag.StringVar(&configs.SmtpUsername, "smtpusername", os.Getenv("SMTP_USERNAME"), "Username of the SMTP server, no authentication when empty")
+	flag.StringVar(&configs.SmtpPassword, "smtppassword", os.Getenv("SMTP_PASSWORD"), "Password of the SMTP server")
+
+	flag.StringVar(&configs.SmtpFrom, "smtpfrom", os.Getenv("SMTP_FROM"), "Sender address of email alerts")
+	if configs.SmtpFrom == "" {
+		configs.SmtpFrom = configs.SmtpUsername
+	}
+
+	flag.StringVar(&configs.EventsBroker, "eventsbroker", os.Getenv("EVENTS_BROKER"), "Message broker ingested videos are published to, nats or kafka")
+	switch configs.EventsBroker {
+	case "":
+		log.Infof("Config: Environment variable EVENTS_BROKER not set. Events are not published.")
+	case "nats", "kafka":
+	default:
+		log.Fatalf("Config: Environment variable EVENTS_BROKER should be nats or kafka. Please refer to README.")
+	}
+
+	flag.StringVar(&configs.NatsUrl, "natsurl", os.Getenv("NATS_URL"), "URL of the NATS server events are published to")
+	if configs.NatsUrl == "" {
+		configs.NatsUrl = DEFAULT_NATS_URL
+	}
+
+	kafkaBrokers := os.Getenv("KAFKA_BROKERS")
+	if kafkaBrokers == "" {
+		kafkaBrokers = DEFAULT_KAFKA_BROKERS
+	}
+	for _, broker := range strings.Split(kafkaBrokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			configs.KafkaBrokers = append(configs.KafkaBrokers, broker)
+		}
+	}
+
+	flag.StringVar(&configs.EventsTopic, "eventstopic", os.Getenv("EVENTS_TOPIC"), "NATS subject or Kafka topic events are published to")
+	if configs.EventsTopic == "" {
+		configs.EventsTopic = DEFAULT_EVENTS_TOPIC
+	}
+
+	flag.Int64Var(&configs.EventsRelaySeconds, "eventsrelayseconds", utils.GetEnvInt("EVENTS_RELAY_SECONDS", DEFAULT_EVENTS_RELAY_SECONDS), "Number of seconds after which events of the outbox are published")
+	if configs.EventsRelaySeconds < 1 {
+		log.Infof("Config: Environment variable EVENTS_RELAY_SECONDS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_EVENTS_RELAY_SECONDS)
+		configs.EventsRelaySeconds = DEFAULT_EVENTS_RELAY_SECONDS
+	}
+
 	flag.Int64Var(&configs.FetchLatestVideosSeconds, "fetchlatestvideosseconds", utils.GetEnvInt("FETCH_LATEST_VIDEOS_SECONDS", DEFAULT_FETCH_LATEST_VIDEOS_SECONDS), "Number of seconds after which latest videos are fetched from youtube and database is updated")
 	if configs.FetchLatestVideosSeconds < 1 {
 		log.Infof("Config: Environment variable FETCH_LATEST_VIDEOS_SECONDS should be greater than 0. Please refer to README. Setting it to default value: %d", DEFAULT_FETCH_LATEST_VIDEOS_SECONDS)
@@ -81,6 +320,10 @@ func GetPort() string {
 	return configs.Port
 }
 
+func GetGrpcPort() string {
+	return configs.GrpcPort
+}
+
 func GetQuery() string {
 	return configs.Query
 }
@@ -93,6 +336,126 @@ func GetPerPageLimit() int64 {
 	return configs.PerPageLimit
 }
 
+func GetMaxPerPageLimit() int64 {
+	return configs.MaxPerPageLimit
+}
+
+func GetRecencyHalfLifeHours() int64 {
+	return configs.RecencyHalfLifeHours
+}
+
+func GetTitleWeight() int64 {
+	return configs.TitleWeight
+}
+
+func GetDescriptionWeight() int64 {
+	return configs.DescriptionWeight
+}
+
+func GetTagsWeight() int64 {
+	return configs.TagsWeight
+}
+
+func GetMinSearchScore() float64 {
+	return configs.MinSearchScore
+}
+
+func GetSuggestMaxPrefixLength() int64 {
+	return configs.SuggestMaxPrefixLength
+}
+
+func GetFuzzyFallbackThreshold() int64 {
+	return configs.FuzzyFallbackThreshold
+}
+
+func GetHighlightPreTag() string {
+	return configs.HighlightPreTag
+}
+
+func GetHighlightPostTag() string {
+	return configs.HighlightPostTag
+}
+
+func GetSnippetLength() int64 {
+	return configs.SnippetLength
+}
+
+func GetMaxBatchGetIds() int64 {
+	return configs.MaxBatchGetIds
+}
+
+func GetStatsSnapshotMinutes() int64 {
+	return configs.StatsSnapshotMinutes
+}
+
+func GetTrendingWindowHours() int64 {
+	return configs.TrendingWindowHours
+}
+
+func GetTrendingMaxTrackedVideos() int64 {
+	return configs.TrendingMaxTrackedVideos
+}
+
+func GetTrendingLikeWeight() float64 {
+	return configs.TrendingLikeWeight
+}
+
+func GetAdminToken() string {
+	return configs.AdminToken
+}
+
+func GetWebhookDeliverySeconds() int64 {
+	return configs.WebhookDeliverySeconds
+}
+
+func GetWebhookMaxAttempts() int64 {
+	return configs.WebhookMaxAttempts
+}
+
+func GetAlertRetrySeconds() int64 {
+	return configs.AlertRetrySeconds
+}
+
+func GetSmtpHost() string {
+	return configs.SmtpHost
+}
+
+func GetSmtpPort() int64 {
+	return configs.SmtpPort
+}
+
+func GetSmtpUsername() string {
+	return configs.SmtpUsername
+}
+
+func GetSmtpPassword() string {
+	return configs.SmtpPassword
+}
+
+func GetSmtpFrom() string {
+	return configs.SmtpFrom
+}
+
+func GetEventsBroker() string {
+	return configs.EventsBroker
+}
+
+func GetNatsUrl() string {
+	return configs.NatsUrl
+}
+
+func GetKafkaBrokers() []string {
+	return configs.KafkaBrokers
+}
+
+func GetEventsTopic() string {
+	return configs.EventsTopic
+}
+
+func GetEventsRelaySeconds() int64 {
+	return configs.EventsRelaySeconds
+}
+
 func GetFetchLatestVideosSeconds() int64 {
 	return configs.FetchLatestVideosSeconds
 }
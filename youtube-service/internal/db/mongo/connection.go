@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/youtube-service/models-services/add_key"
+	"github.com/youtube-service/models-services/alerts"
 	"github.com/youtube-service/models-services/get_video-search_video"
+	"github.com/youtube-service/models-services/outbox"
+	"github.com/youtube-service/models-services/webhooks"
 	"github.com/youtube-service/internal/configs"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -17,8 +20,27 @@ import (
 func ConnectionDb() {
 	client := ConnectToMongoDb()
 	get_video-search_video.SetCollection(client)
+	get_video-search_video.MigratePublishedAtToDate()
+	get_video-search_video.MigrateTextLanguage()
 	get_video-search_video.CreateTitleAndDescriptionIndex()
+	get_video-search_video.CreatePublishedAtIndex()
+	get_video-search_video.MigrateDuplicateUniqueIds()
+	get_video-search_video.CreateUniqueIdIndex()
+	get_video-search_video.CreateFilterIndexes()
+	get_video-search_video.CreateTrendingIndexes()
+	get_video-search_video.CreateIngestionRunIndexes()
+	get_video-search_video.MigrateTitlePrefixes()
+	get_video-search_video.CreateSuggestIndexes()
+	get_video-search_video.CreateDictionaryIndexes()
+	get_video-search_video.MigrateDictionary()
+	get_video-search_video.MigrateDictionaryGrams()
 	apikeys.SetCollection(client)
+	webhooks.SetCollection(client)
+	webhooks.CreateIndexes()
+	alerts.SetCollection(client)
+	alerts.CreateIndexes()
+	outbox.SetCollection(client)
+	outbox.CreateIndexes()
 }
 
 func ConnectToMongoDb() *mongo.Client {
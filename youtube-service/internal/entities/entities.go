@@ -2,12 +2,158 @@ package entities
 
 import "time"
 
+const (
+	DurationShort  = "short"
+	DurationMedium = "medium"
+	DurationLong   = "long"
+)
+
+const (
+	SortRelevance = "relevance"
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+	SortViews     = "views"
+	SortLikes     = "likes"
+	SortTrending  = "trending"
+)
+
+const (
+	FacetChannel  = "channel"
+	FacetMonth    = "month"
+	FacetDuration = "duration"
+	FacetTopic    = "topic"
+)
+
 type Video struct {
-	Id          string `json:"_id,omitempty" bson:"_id,omitempty"`
-	UniqueId    string `json:"uniqueId" bson:"uniqueId"`
-	Title       string `json:"title" bson:"title"`
-	Description string `json:"description" bson:"description"`
-	PublishedAt string `json:"publishedAt" bson:"publishedAt"`
+	Id                   string    `json:"_id,omitempty" bson:"_id,omitempty"`
+	UniqueId             string    `json:"uniqueId" bson:"uniqueId"`
+	Title                string    `json:"title" bson:"title"`
+	Description          string    `json:"description" bson:"description"`
+	PublishedAt          time.Time `json:"publishedAt" bson:"publishedAt"`
+	ChannelId            string    `json:"channelId" bson:"channelId"`
+	ChannelTitle         string    `json:"channelTitle" bson:"channelTitle"`
+	SourceQuery          string    `json:"sourceQuery" bson:"sourceQuery"`
+	DurationSeconds      int64     `json:"durationSeconds" bson:"durationSeconds"`
+	DurationBucket       string    `json:"durationBucket" bson:"durationBucket"`
+	ViewCount            int64     `json:"viewCount" bson:"viewCount"`
+	LikeCount            int64     `json:"likeCount" bson:"likeCount"`
+	DefaultLanguage      string    `json:"defaultLanguage" bson:"defaultLanguage"`
+	LiveBroadcastContent string    `json:"liveBroadcastContent" bson:"liveBroadcastContent"`
+	Tags                 []string  `json:"tags" bson:"tags"`
+	// Language in which the video is indexed for text search
+	TextLanguage string `json:"textLanguage" bson:"textLanguage"`
+	// Only set for videos whose statistics grew over the trending window
+	Trending *Trending `json:"trending,omitempty" bson:"trending,omitempty"`
+	// Text search score, only set for search results
+	Score float64 `json:"-" bson:"score,omitempty"`
+	// Text search score before blending with recency, only set for search results
+	TextScore float64 `json:"-" bson:"textScore,omitempty"`
+	// Only set for search results in debug mode
+	ScoreBreakdown *ScoreBreakdown `json:"scoreBreakdown,omitempty" bson:"-"`
+	// Only set for search results
+	Highlights *Highlights `json:"highlights,omitempty" bson:"-"`
+}
+
+// View and like growth of a video over the trending window
+type Trending struct {
+	// Views and likes gained per hour
+	ViewVelocity float64   `json:"viewVelocity" bson:"viewVelocity"`
+	LikeVelocity float64   `json:"likeVelocity" bson:"likeVelocity"`
+	Score        float64   `json:"score" bson:"score"`
+	ComputedAt   time.Time `json:"computedAt" bson:"computedAt"`
+}
+
+// Title and description excerpt of a search result with the matched terms wrapped in markers
+type Highlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Explains the search score of a video
+type ScoreBreakdown struct {
+	Score             float64      `json:"score"`
+	TextScore         float64      `json:"textScore"`
+	RecencyMultiplier float64      `json:"recencyMultiplier"`
+	MinScore          float64      `json:"minScore"`
+	Fields            []FieldMatch `json:"fields"`
+}
+
+// Query terms found in a text indexed field
+type FieldMatch struct {
+	Field        string   `json:"field"`
+	Weight       int64    `json:"weight"`
+	MatchedTerms []string `json:"matchedTerms"`
+}
+
+// Pagination and sort order requested by a client. Cursor takes precedence over Page when set.
+type PageRequest struct {
+	Page    int64
+	PerPage int64
+	Cursor  string
+	Sort    string
+	// Half life in hours of the recency decay blended into relevance. 0 disables it.
+	RecencyHalfLifeHours float64
+	// Adds the score breakdown to search results
+	Debug bool
+	// Markers wrapping the matched terms in the highlights of search results
+	HighlightPreTag  string
+	HighlightPostTag string
+	// Facets counted for search results
+	Facets []string
+}
+
+// Filters applied when listing or searching videos. Zero values are not filtered on.
+type VideoFilter struct {
+	PublishedAfter       *time.Time
+	PublishedBefore      *time.Time
+	ChannelId            string
+	SourceQuery          string
+	DurationBucket       string
+	MinViewCount         int64
+	DefaultLanguage      string
+	LiveBroadcastContent string
+}
+
+// A page of videos along with what is needed to fetch the next one
+type VideoPage struct {
+	Videos     []Video
+	Total      int64
+	HasMore    bool
+	NextCursor string
+	// Corrections of a search query with few results
+	DidYouMean []string
+	// Set when the results are those of the first did you mean suggestion instead of the query
+	CorrectedQuery string
+	// Number of matched videos per value of each requested facet
+	Facets map[string][]FacetCount
+}
+
+// Number of videos having a value of a facet. Label is the display name of the value, e.g. the
+// channel title of a channel id, when it differs from the value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+const (
+	IngestionSucceeded = "succeeded"
+	IngestionSkipped   = "skipped"
+	IngestionFailed    = "failed"
+)
+
+// A run of the job fetching the latest videos from youtube
+type IngestionRun struct {
+	Id         string    `json:"_id,omitempty" bson:"_id,omitempty"`
+	Query      string    `json:"query" bson:"query"`
+	StartedAt  time.Time `json:"startedAt" bson:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt" bson:"finishedAt"`
+	// Skipped when the search results did not change since the previous run
+	Status string `json:"status" bson:"status"`
+	// Number of videos fetched and of those which were not stored yet
+	Fetched  int64  `json:"fetched" bson:"fetched"`
+	Inserted int64  `json:"inserted" bson:"inserted"`
+	Error    string `json:"error,omitempty" bson:"error,omitempty"`
 }
 
 type ApiKey struct {
@@ -16,3 +162,71 @@ type ApiKey struct {
 	IsExpired   bool      `json:"isExpired" bson:"isExpired"`
 	LastUpdated time.Time `json:"lastUpdated" bson:"lastUpdated"`
 }
+
+const (
+	DeliveryPending      = "pending"
+	DeliverySucceeded    = "succeeded"
+	DeliveryDeadLettered = "dead_lettered"
+)
+
+// Subscription of a URL to the ingested videos matching its filters. Empty filters match every video.
+type Webhook struct {
+	Id  string `json:"_id,omitempty" bson:"_id,omitempty"`
+	Url string `json:"url" bson:"url"`
+	// Key of the HMAC signature of the payloads, only returned when the webhook is created
+	Secret    string `json:"-" bson:"secret"`
+	Topic     string `json:"topic" bson:"topic"`
+	ChannelId string `json:"channelId" bson:"channelId"`
+	// Search query which the videos must match
+	Keyword   string    `json:"keyword" bson:"keyword"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Delivery of an ingested video to a webhook, retried until it succeeds or is dead lettered
+type WebhookDelivery struct {
+	Id        string `json:"_id,omitempty" bson:"_id,omitempty"`
+	WebhookId string `json:"webhookId" bson:"webhookId"`
+	// YouTube id of the delivered video
+	VideoId string `json:"videoId" bson:"videoId"`
+	Status  string `json:"status" bson:"status"`
+	// Signed body, sent as is on every attempt
+	Payload  string            `json:"-" bson:"payload"`
+	Attempts []DeliveryAttempt `json:"attempts" bson:"attempts"`
+	// Number of attempts which failed since the delivery was enqueued or redelivered
+	FailedAttempts int64     `json:"failedAttempts" bson:"failedAttempts"`
+	NextAttemptAt  time.Time `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	CreatedAt      time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+type DeliveryAttempt struct {
+	At time.Time `json:"at" bson:"at"`
+	// HTTP status of the response, 0 when no response was received
+	StatusCode int    `json:"statusCode" bson:"statusCode"`
+	Error      string `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs int64  `json:"durationMs" bson:"durationMs"`
+}
+
+const (
+	NotifierSlack = "slack"
+	NotifierEmail = "email"
+)
+
+// Rule alerting on the ingested videos matching its watch phrase. Each video is alerted on once
+// per rule.
+type AlertRule struct {
+	Id   string `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name string `json:"name" bson:"name"`
+	// Search query which the videos must match, e.g. a brand name
+	Query string `json:"query" bson:"query"`
+	// Optional filters on the query the videos were fetched for and their channel
+	Topic     string `json:"topic" bson:"topic"`
+	ChannelId string `json:"channelId" bson:"channelId"`
+	// Plugin sending the alerts, slack or email
+	Notifier string `json:"notifier" bson:"notifier"`
+	// Slack compatible incoming webhook of slack notifiers
+	SlackUrl string `json:"slackUrl,omitempty" bson:"slackUrl,omitempty"`
+	// Addresses of email notifiers
+	Recipients []string  `json:"recipients,omitempty" bson:"recipients,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+)
+
+const (
+	specVersion = "1.0"
+	// Source of the events, the same for every instance of the service
+	source = "/youtube-service/videos"
+	// Type of the events of videos inserted in the database by the ingestion
+	TypeVideoIngested = "com.youtube-service.video.ingested"
+	// Media type of events encoded in the structured mode of CloudEvents
+	ContentType = "application/cloudevents+json"
+)
+
+// Event is a CloudEvents 1.0 event encoded as JSON in structured mode
+type Event struct {
+	SpecVersion string `json:"specversion"`
+	Id          string `json:"id"`
+	Source      string `json:"source"`
+	Type        string `json:"type"`
+	// YouTube id of the video
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            VideoData `json:"data"`
+}
+
+// VideoData is the data of video events. It is decoupled from entities.Video so that consumers
+// don't depend on how videos are stored.
+type VideoData struct {
+	UniqueId             string    `json:"uniqueId"`
+	Url                  string    `json:"url"`
+	Title                string    `json:"title"`
+	Description          string    `json:"description"`
+	PublishedAt          time.Time `json:"publishedAt"`
+	ChannelId            string    `json:"channelId"`
+	ChannelTitle         string    `json:"channelTitle"`
+	SourceQuery          string    `json:"sourceQuery"`
+	DurationSeconds      int64     `json:"durationSeconds"`
+	DurationBucket       string    `json:"durationBucket"`
+	ViewCount            int64     `json:"viewCount"`
+	LikeCount            int64     `json:"likeCount"`
+	DefaultLanguage      string    `json:"defaultLanguage"`
+	LiveBroadcastContent string    `json:"liveBroadcastContent"`
+	Tags                 []string  `json:"tags"`
+}
+
+// Returns the event of the ingestion of the video. The id is kept on every publication of the
+// event so that consumers can ignore duplicates.
+func NewVideoIngested(id string, video entities.Video, at time.Time) Event {
+	tags := video.Tags
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+	return Event{
+		SpecVersion:     specVersion,
+		Id:              id,
+		Source:          source,
+		Type:            TypeVideoIngested,
+		Subject:         video.UniqueId,
+		Time:            at,
+		DataContentType: "application/json",
+		Data: VideoData{
+			UniqueId:             video.UniqueId,
+			Url:                  "https://www.youtube.com/watch?v=" + video.UniqueId,
+			Title:                video.Title,
+			Description:          video.Description,
+			PublishedAt:          video.PublishedAt,
+			ChannelId:            video.ChannelId,
+			ChannelTitle:         video.ChannelTitle,
+			SourceQuery:          video.SourceQuery,
+			DurationSeconds:      video.DurationSeconds,
+			DurationBucket:       video.DurationBucket,
+			ViewCount:            video.ViewCount,
+			LikeCount:            video.LikeCount,
+			DefaultLanguage:      video.DefaultLanguage,
+			LiveBroadcastContent: video.LiveBroadcastContent,
+			Tags:                 tags,
+		},
+	}
+}
+
+func (e Event) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
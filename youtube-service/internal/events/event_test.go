@@ -0,0 +1,83 @@
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+)
+
+func TestNewVideoIngestedEncoding(t *testing.T) {
+	at := time.Date(2022, 9, 29, 16, 0, 0, 0, time.UTC)
+	video := entities.Video{
+		UniqueId:     "dQw4w9WgXcQ",
+		Title:        "India vs Australia",
+		PublishedAt:  time.Date(2022, 9, 28, 10, 30, 0, 0, time.UTC),
+		ChannelId:    "UCabc",
+		ChannelTitle: "Cricket",
+		SourceQuery:  "cricket",
+		ViewCount:    42,
+	}
+	data, err := NewVideoIngested("6335c6c2e4b0a1f2c3d4e5f6", video, at).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned error %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("event is not a JSON object: %v", err)
+	}
+	attributes := map[string]interface{}{
+		"specversion":     "1.0",
+		"id":              "6335c6c2e4b0a1f2c3d4e5f6",
+		"source":          "/youtube-service/videos",
+		"type":            "com.youtube-service.video.ingested",
+		"subject":         "dQw4w9WgXcQ",
+		"time":            "2022-09-29T16:00:00Z",
+		"datacontenttype": "application/json",
+	}
+	for name, want := range attributes {
+		if got[name] != want {
+			t.Errorf("attribute %v = %v, want %v", name, got[name], want)
+		}
+	}
+
+	videoData, ok := got["data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("data = %v, want an object", got["data"])
+	}
+	fields := map[string]interface{}{
+		"uniqueId":    "dQw4w9WgXcQ",
+		"url":         "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"title":       "India vs Australia",
+		"publishedAt": "2022-09-28T10:30:00Z",
+		"channelId":   "UCabc",
+		"sourceQuery": "cricket",
+		"viewCount":   float64(42),
+		// Videos without tags have an empty list rather than null
+		"tags": []interface{}{},
+	}
+	for name, want := range fields {
+		if !reflect.DeepEqual(videoData[name], want) {
+			t.Errorf("data.%v = %#v, want %#v", name, videoData[name], want)
+		}
+	}
+}
+
+// Events decoded from the outbox are published unchanged
+func TestEventRoundTrip(t *testing.T) {
+	event := NewVideoIngested("6335c6c2e4b0a1f2c3d4e5f6", entities.Video{UniqueId: "dQw4w9WgXcQ", Tags: []string{"ipl"}}, time.Date(2022, 9, 29, 16, 0, 0, 0, time.UTC))
+	data, err := event.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned error %v", err)
+	}
+	var decoded Event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal returned error %v", err)
+	}
+	if !reflect.DeepEqual(decoded, event) {
+		t.Errorf("decoded event = %+v, want %+v", decoded, event)
+	}
+}
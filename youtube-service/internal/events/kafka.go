@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Publishes events to a Kafka topic, keyed by the YouTube id of the video so that the events of a
+// video stay ordered in its partition
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafkaPublisher(brokers []string, topic string) *kafkaPublisher {
+	return &kafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Each event is written on its own, so it is sent right away instead of waiting for the
+		// default 1s for a batch to fill
+		BatchTimeout: 10 * time.Millisecond,
+	}}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := event.Marshal()
+	if err != nil {
+		return err
+	}
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.Subject),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte(ContentType)},
+		},
+	})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Time after which publishing an event fails unless the context has an earlier deadline
+const publishTimeout = 10 * time.Second
+
+// Publishes events to a NATS subject. The subject can be captured by a JetStream stream, which
+// also de-duplicates events by their Nats-Msg-Id header.
+type natsPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func newNatsPublisher(url string, subject string) (*natsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("youtube-service"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{conn: conn, subject: subject}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := event.Marshal()
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(p.subject)
+	msg.Header.Set("Content-Type", ContentType)
+	msg.Header.Set(nats.MsgIdHdr, event.Id)
+	msg.Data = data
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// Waits for the server to have processed the message. Flushing requires a deadline.
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+
+	"github.com/youtube-service/internal/configs"
+)
+
+// Publisher sends events to a message broker. Publish returns once the broker has accepted the
+// event, so that events which failed can be published again.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+// Returns the publisher of the broker set by EVENTS_BROKER, or nil when events are not published
+func NewPublisher() (Publisher, error) {
+	switch configs.GetEventsBroker() {
+	case "nats":
+		publisher, err := newNatsPublisher(configs.GetNatsUrl(), configs.GetEventsTopic())
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	case "kafka":
+		return newKafkaPublisher(configs.GetKafkaBrokers(), configs.GetEventsTopic()), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,276 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	generator = "youtube-service"
+	mediaNS   = "http://search.yahoo.com/mrss/"
+	atomNS    = "http://www.w3.org/2005/Atom"
+	dcNS      = "http://purl.org/dc/elements/1.1/"
+	// Size of the hqdefault thumbnails of YouTube
+	thumbnailWidth  = 480
+	thumbnailHeight = 360
+)
+
+// Feed of stored videos, newest first
+type Feed struct {
+	Title       string
+	Description string
+	// Absolute URL of the feed, also used as the id of Atom feeds
+	Url string
+	// Page the feed is about
+	HomePageUrl string
+	Videos      []entities.Video
+}
+
+// Returns the time the feed last changed, when its newest video was ingested. Zero for empty feeds.
+func (f Feed) Updated() time.Time {
+	var updated time.Time
+	for _, video := range f.Videos {
+		if ingested := IngestedAt(video); ingested.After(updated) {
+			updated = ingested
+		}
+	}
+	return updated
+}
+
+// Returns the time the video was inserted in the database, from the timestamp of its object id.
+// Videos are not changed by later ingestions apart from their statistics, which feeds don't include,
+// so it is the updated time of their entries.
+func IngestedAt(video entities.Video) time.Time {
+	id, err := primitive.ObjectIDFromHex(video.Id)
+	if err != nil {
+		return video.PublishedAt
+	}
+	return id.Timestamp()
+}
+
+// Stable id of the entries of a video, the one YouTube uses in its own feeds
+func entryId(video entities.Video) string {
+	return "yt:video:" + video.UniqueId
+}
+
+func videoUrl(video entities.Video) string {
+	return "https://www.youtube.com/watch?v=" + video.UniqueId
+}
+
+func channelUrl(video entities.Video) string {
+	return "https://www.youtube.com/channel/" + video.ChannelId
+}
+
+func thumbnailUrl(video entities.Video) string {
+	return "https://i.ytimg.com/vi/" + video.UniqueId + "/hqdefault.jpg"
+}
+
+// Times of empty feeds, so that they render the same on every request
+func updatedOrEpoch(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return t
+}
+
+type link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type mediaThumbnail struct {
+	Url    string `xml:"url,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"feed"`
+	NS        string      `xml:"xmlns,attr"`
+	MediaNS   string      `xml:"xmlns:media,attr"`
+	Id        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle"`
+	Updated   string      `xml:"updated"`
+	Links     []link      `xml:"link"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Id        string         `xml:"id"`
+	Title     string         `xml:"title"`
+	Link      link           `xml:"link"`
+	Published string         `xml:"published"`
+	Updated   string         `xml:"updated"`
+	Author    atomAuthor     `xml:"author"`
+	Summary   string         `xml:"summary"`
+	Thumbnail mediaThumbnail `xml:"media:thumbnail"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	Uri  string `xml:"uri"`
+}
+
+// Renders the feed as an Atom 1.0 document
+func Atom(f Feed) ([]byte, error) {
+	feed := atomFeed{
+		NS:       atomNS,
+		MediaNS:  mediaNS,
+		Id:       f.Url,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  updatedOrEpoch(f.Updated()).UTC().Format(time.RFC3339),
+		Links: []link{
+			{Href: f.Url, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.HomePageUrl, Rel: "alternate", Type: "text/html"},
+		},
+		Generator: generator,
+		Entries:   make([]atomEntry, 0, len(f.Videos)),
+	}
+	for _, video := range f.Videos {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Id:        entryId(video),
+			Title:     video.Title,
+			Link:      link{Href: videoUrl(video), Rel: "alternate", Type: "text/html"},
+			Published: video.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:   IngestedAt(video).UTC().Format(time.RFC3339),
+			Author:    atomAuthor{Name: video.ChannelTitle, Uri: channelUrl(video)},
+			Summary:   video.Description,
+			Thumbnail: mediaThumbnail{Url: thumbnailUrl(video), Width: thumbnailWidth, Height: thumbnailHeight},
+		})
+	}
+	return marshalXML(feed)
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	MediaNS string     `xml:"xmlns:media,attr"`
+	DcNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          link      `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Guid        rssGuid        `xml:"guid"`
+	PubDate     string         `xml:"pubDate"`
+	Creator     string         `xml:"dc:creator"`
+	Description string         `xml:"description"`
+	Thumbnail   mediaThumbnail `xml:"media:thumbnail"`
+}
+
+type rssGuid struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// Renders the feed as an RSS 2.0 document
+func RSS(f Feed) ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  atomNS,
+		MediaNS: mediaNS,
+		DcNS:    dcNS,
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.HomePageUrl,
+			Description:   f.Description,
+			Self:          link{Href: f.Url, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: updatedOrEpoch(f.Updated()).UTC().Format(time.RFC1123Z),
+			Generator:     generator,
+			Items:         make([]rssItem, 0, len(f.Videos)),
+		},
+	}
+	for _, video := range f.Videos {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       video.Title,
+			Link:        videoUrl(video),
+			Guid:        rssGuid{Value: entryId(video)},
+			PubDate:     video.PublishedAt.UTC().Format(time.RFC1123Z),
+			Creator:     video.ChannelTitle,
+			Description: video.Description,
+			Thumbnail:   mediaThumbnail{Url: thumbnailUrl(video), Width: thumbnailWidth, Height: thumbnailHeight},
+		})
+	}
+	return marshalXML(doc)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// Document of https://www.jsonfeed.org/version/1.1/
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	HomePageUrl string         `json:"home_page_url"`
+	FeedUrl     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	Id            string           `json:"id"`
+	Url           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentText   string           `json:"content_text"`
+	Image         string           `json:"image"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// Renders the feed as a JSON Feed 1.1 document
+func JSON(f Feed) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		Description: f.Description,
+		HomePageUrl: f.HomePageUrl,
+		FeedUrl:     f.Url,
+		Items:       make([]jsonFeedItem, 0, len(f.Videos)),
+	}
+	for _, video := range f.Videos {
+		feed.Items = append(feed.Items, jsonFeedItem{
+			Id:            entryId(video),
+			Url:           videoUrl(video),
+			Title:         video.Title,
+			ContentText:   video.Description,
+			Image:         thumbnailUrl(video),
+			DatePublished: video.PublishedAt.UTC().Format(time.RFC3339),
+			DateModified:  IngestedAt(video).UTC().Format(time.RFC3339),
+			Authors:       []jsonFeedAuthor{{Name: video.ChannelTitle, Url: channelUrl(video)}},
+			Tags:          video.Tags,
+		})
+	}
+	return json.MarshalIndent(feed, "", "  ")
+}
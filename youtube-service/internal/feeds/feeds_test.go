@@ -0,0 +1,155 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ingested = time.Date(2022, 9, 29, 16, 0, 0, 0, time.UTC)
+	video    = entities.Video{
+		Id:           primitive.NewObjectIDFromTimestamp(ingested).Hex(),
+		UniqueId:     "dQw4w9WgXcQ",
+		Title:        "India vs Australia <highlights>",
+		Description:  "Full match & more",
+		PublishedAt:  time.Date(2022, 9, 28, 10, 30, 0, 0, time.UTC),
+		ChannelId:    "UCabc",
+		ChannelTitle: "Cricket",
+		Tags:         []string{"cricket"},
+	}
+	feed = Feed{
+		Title:       "YouTube videos: cricket",
+		Description: "Newest videos fetched from YouTube for cricket",
+		Url:         "http://localhost:3500/feeds/cricket.atom",
+		HomePageUrl: "https://www.youtube.com/results?search_query=cricket",
+		Videos: []entities.Video{
+			video,
+			// Stored an hour earlier
+			{Id: primitive.NewObjectIDFromTimestamp(ingested.Add(-time.Hour)).Hex(), UniqueId: "aaaaaaaaaaa", PublishedAt: ingested.Add(-2 * time.Hour)},
+		},
+	}
+)
+
+func TestUpdated(t *testing.T) {
+	if got := feed.Updated(); !got.Equal(ingested) {
+		t.Errorf("Updated() = %v, want the time the newest video was stored %v", got, ingested)
+	}
+	if got := (Feed{}).Updated(); !got.IsZero() {
+		t.Errorf("Updated() of an empty feed = %v, want zero", got)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := Atom(feed)
+	if err != nil {
+		t.Fatalf("Atom returned error %v", err)
+	}
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">`,
+		`<id>http://localhost:3500/feeds/cricket.atom</id>`,
+		`<updated>2022-09-29T16:00:00Z</updated>`,
+		`<link href="http://localhost:3500/feeds/cricket.atom" rel="self" type="application/atom+xml"></link>`,
+		`<id>yt:video:dQw4w9WgXcQ</id>`,
+		`<title>India vs Australia &lt;highlights&gt;</title>`,
+		`<link href="https://www.youtube.com/watch?v=dQw4w9WgXcQ" rel="alternate" type="text/html"></link>`,
+		`<published>2022-09-28T10:30:00Z</published>`,
+		`<media:thumbnail url="https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" width="480" height="360"></media:thumbnail>`,
+		`<updated>2022-09-29T15:00:00Z</updated>`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Atom feed has no %s:\n%s", want, body)
+		}
+	}
+	if err := xml.Unmarshal(body, new(interface{})); err != nil {
+		t.Errorf("Atom feed is not well-formed: %v", err)
+	}
+}
+
+func TestRSS(t *testing.T) {
+	body, err := RSS(feed)
+	if err != nil {
+		t.Fatalf("RSS returned error %v", err)
+	}
+	for _, want := range []string{
+		`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		`<atom:link href="http://localhost:3500/feeds/cricket.atom" rel="self" type="application/rss+xml"></atom:link>`,
+		`<lastBuildDate>Thu, 29 Sep 2022 16:00:00 +0000</lastBuildDate>`,
+		`<guid isPermaLink="false">yt:video:dQw4w9WgXcQ</guid>`,
+		`<pubDate>Wed, 28 Sep 2022 10:30:00 +0000</pubDate>`,
+		`<dc:creator>Cricket</dc:creator>`,
+		`<description>Full match &amp; more</description>`,
+		`<media:thumbnail url="https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" width="480" height="360"></media:thumbnail>`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("RSS feed has no %s:\n%s", want, body)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	body, err := JSON(feed)
+	if err != nil {
+		t.Fatalf("JSON returned error %v", err)
+	}
+	var got jsonFeed
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("JSON feed is not valid JSON: %v", err)
+	}
+	if got.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %q, want JSON Feed 1.1", got.Version)
+	}
+	if got.FeedUrl != feed.Url || got.HomePageUrl != feed.HomePageUrl {
+		t.Errorf("feed_url, home_page_url = %q, %q, want %q, %q", got.FeedUrl, got.HomePageUrl, feed.Url, feed.HomePageUrl)
+	}
+	if len(got.Items) != 2 {
+		t.Fatalf("JSON feed has %d items, want 2", len(got.Items))
+	}
+	item := got.Items[0]
+	want := jsonFeedItem{
+		Id:            "yt:video:dQw4w9WgXcQ",
+		Url:           "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		Title:         "India vs Australia <highlights>",
+		ContentText:   "Full match & more",
+		Image:         "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg",
+		DatePublished: "2022-09-28T10:30:00Z",
+		DateModified:  "2022-09-29T16:00:00Z",
+		Authors:       []jsonFeedAuthor{{Name: "Cricket", Url: "https://www.youtube.com/channel/UCabc"}},
+		Tags:          []string{"cricket"},
+	}
+	if gotJSON, wantJSON := mustMarshal(t, item), mustMarshal(t, want); gotJSON != wantJSON {
+		t.Errorf("item = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+// Empty feeds have no items rather than null, and render the same time on every request
+func TestEmptyFeed(t *testing.T) {
+	body, err := JSON(Feed{Title: "empty"})
+	if err != nil {
+		t.Fatalf("JSON returned error %v", err)
+	}
+	if !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("empty JSON feed has no empty items: %s", body)
+	}
+	body, err = Atom(Feed{Title: "empty"})
+	if err != nil {
+		t.Fatalf("Atom returned error %v", err)
+	}
+	if !strings.Contains(string(body), "<updated>1970-01-01T00:00:00Z</updated>") {
+		t.Errorf("empty Atom feed is not updated at the epoch: %s", body)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal returned error %v", err)
+	}
+	return string(data)
+}
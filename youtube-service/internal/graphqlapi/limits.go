@@ -0,0 +1,228 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/youtube-service/internal/configs"
+)
+
+const (
+	// Max nesting of the fields of an operation, e.g. 6 for search { nodes { channel { videos { nodes { title } } } } }
+	maxQueryDepth = 10
+	// Max complexity of an operation. Every field costs 1, and the fields selected under a paginated
+	// field cost as many times as the number of videos it returns, so that nested connections such as
+	// channel { videos } of every video of a page are bounded.
+	maxQueryComplexity = 2500
+	// Max number of pages of an operation, each fetched by a database query, e.g. 6 for the channel videos
+	// of 5 search results
+	maxQueryPages = 20
+)
+
+// Checks the depth and complexity of the operation of the request before it is executed. Returns nil
+// for requests which can't be parsed, whose errors are reported by the execution.
+// Introspection fields are not counted as they are resolved without querying the database.
+func checkLimits(req Request) error {
+	operations, fragments, err := parseRequest(req)
+	if err != nil {
+		return nil
+	}
+
+	l := limits{fragments: fragments, variables: req.Variables}
+	for _, operation := range operations {
+		var root graphql.Type = schema.QueryType()
+		if operation.Operation == ast.OperationTypeMutation && schema.MutationType() != nil {
+			root = schema.MutationType()
+		}
+		cost := l.selectionSet(operation.SelectionSet, root, make(map[string]bool))
+		if cost.depth > maxQueryDepth {
+			return fmt.Errorf("query is nested %d levels deep, more than the max of %d", cost.depth, maxQueryDepth)
+		}
+		if cost.complexity > maxQueryComplexity {
+			return fmt.Errorf("query has a complexity of %d, more than the max of %d, request fewer videos or fewer nested fields", cost.complexity, maxQueryComplexity)
+		}
+		if cost.pages > maxQueryPages {
+			return fmt.Errorf("query fetches %d pages, more than the max of %d, request fewer videos or fewer nested connections", cost.pages, maxQueryPages)
+		}
+	}
+	return nil
+}
+
+// Returns true unless the request selects an operation which is not a query, e.g. a mutation, which must
+// not be executed by GET requests. Requests which can't be parsed are reported by the execution.
+func IsQuery(req Request) bool {
+	operations, _, err := parseRequest(req)
+	if err != nil {
+		return true
+	}
+	for _, operation := range operations {
+		if operation.Operation != ast.OperationTypeQuery {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the operations of the request selected by its operation name, all of them without name, and
+// the fragments of the request by name
+func parseRequest(req Request) ([]*ast.OperationDefinition, map[string]*ast.FragmentDefinition, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query)})})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	operations := make([]*ast.OperationDefinition, 0)
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if req.OperationName == "" || (d.Name != nil && d.Name.Value == req.OperationName) {
+				operations = append(operations, d)
+			}
+		}
+	}
+	return operations, fragments, nil
+}
+
+type cost struct {
+	depth      int
+	complexity int
+	pages      int
+}
+
+type limits struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// Returns the cost of a selection set of the parent type. Fragments already spread on the path are
+// skipped, as validation rejects such cycles anyway.
+func (l limits) selectionSet(set *ast.SelectionSet, parent graphql.Type, spread map[string]bool) cost {
+	total := cost{}
+	if set == nil {
+		return total
+	}
+	for _, selection := range set.Selections {
+		var c cost
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			definition := fieldDefinition(parent, s.Name.Value)
+			var fieldType graphql.Type
+			if definition != nil {
+				fieldType = definition.Type
+			}
+			c = l.selectionSet(s.SelectionSet, fieldType, spread)
+			multiplier, paginated := l.multiplier(s, definition)
+			c.depth++
+			c.complexity = 1 + multiplier*c.complexity
+			c.pages *= multiplier
+			if paginated {
+				c.pages++
+			}
+		case *ast.InlineFragment:
+			c = l.selectionSet(s.SelectionSet, fragmentType(s.TypeCondition, parent), spread)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := l.fragments[name]
+			if !ok || spread[name] {
+				continue
+			}
+			spread[name] = true
+			c = l.selectionSet(fragment.SelectionSet, fragmentType(fragment.TypeCondition, parent), spread)
+			delete(spread, name)
+		}
+		if c.depth > total.depth {
+			total.depth = c.depth
+		}
+		total.complexity += c.complexity
+		total.pages += c.pages
+	}
+	return total
+}
+
+// Returns the definition of the field of the type, or nil when it has no such field, which is
+// reported by the validation of the request
+func fieldDefinition(parent graphql.Type, name string) *graphql.FieldDefinition {
+	switch t := graphql.GetNamed(parent).(type) {
+	case *graphql.Object:
+		if t != nil {
+			return t.Fields()[name]
+		}
+	case *graphql.Interface:
+		if t != nil {
+			return t.Fields()[name]
+		}
+	}
+	return nil
+}
+
+// Returns the type of the condition of a fragment, or the parent type when it has none
+func fragmentType(condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil {
+		return parent
+	}
+	return schema.Type(condition.Name.Value)
+}
+
+// Returns the number of times the fields selected under the field are resolved, and whether the field
+// is paginated. Paginated fields, which have a first argument, return their first argument of items, the
+// default page size when it is not set, and the max page size when it is set by a variable which is not
+// an integer or is above it.
+func (l limits) multiplier(field *ast.Field, definition *graphql.FieldDefinition) (int, bool) {
+	if definition == nil || !hasArgument(definition, "first") {
+		return 1, false
+	}
+	if field.Name.Value == "ingestionRuns" {
+		return l.first(field, defaultIngestionRuns, maxIngestionRuns), true
+	}
+	return l.first(field, int(configs.GetPerPageLimit()), int(configs.GetMaxPerPageLimit())), true
+}
+
+func hasArgument(definition *graphql.FieldDefinition, name string) bool {
+	for _, argument := range definition.Args {
+		if argument.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (l limits) first(field *ast.Field, defaultValue int, maxValue int) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+		var value interface{} = argument.Value.GetValue()
+		if variable, ok := argument.Value.(*ast.Variable); ok {
+			value = l.variables[variable.Name.Value]
+		}
+		n := 0
+		switch v := value.(type) {
+		case string:
+			// Int literals are kept as strings by the parser
+			n, _ = strconv.Atoi(v)
+		case float64:
+			// Variables decoded from JSON are floats
+			if v <= float64(maxValue) {
+				n = int(v)
+			}
+		case int:
+			n = v
+		}
+		if n < 1 || n > maxValue {
+			return maxValue
+		}
+		return n
+	}
+	return defaultValue
+}
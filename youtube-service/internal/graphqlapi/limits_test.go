@@ -0,0 +1,88 @@
+package graphqlapi
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/youtube-service/internal/configs"
+)
+
+func TestMain(m *testing.M) {
+	// Page sizes are read from the configs, which are left to their defaults
+	os.Setenv("PORT", ":3500")
+	os.Setenv("MONGODB_URI", "mongodb://localhost:27017")
+	os.Setenv("QUERY", "cricket")
+	configs.InitConfig()
+
+	// Built the same way as by Execute, which then reuses it
+	var err error
+	schemaOnce.Do(func() {
+		schema, err = newSchema()
+	})
+	if err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCheckLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		rejected  bool
+	}{
+		{"page of videos", `{ videos { nodes { uniqueId title channel { id title } } } }`, nil, false},
+		{"max page of search results", `{ search(query: "cricket", first: 50) { nodes { uniqueId title description tags trending { score } } totalCount } }`, nil, false},
+		{"channel videos of every search result", `{ search(query: "cricket", first: 50) { nodes { channel { videos(first: 50) { nodes { title } } } } } }`, nil, true},
+		{"channel videos by a variable", `query($n: Int) { search(query: "cricket", first: 50) { nodes { channel { videos(first: $n) { nodes { title } } } } } }`, map[string]interface{}{"n": float64(50)}, true},
+		{"channel videos of few search results", `{ search(query: "cricket", first: 5) { nodes { channel { videos(first: 5) { nodes { title } } } } } }`, nil, false},
+		{"channel video of every search result", `{ search(query: "cricket", first: 50) { nodes { channel { videos(first: 1) { nodes { title } } } } } }`, nil, true},
+		{"channel videos in a fragment", `{ videos { nodes { ...ch } } } fragment ch on Video { channel { videos { nodes { channel { videos { nodes { title } } } } } } }`, nil, true},
+		{"too deep", `{ video(uniqueId: "dQw4w9WgXcQ") { channel { videos(first: 1) { nodes { channel { videos(first: 1) { nodes { channel { videos(first: 1) { nodes { title } } } } } } } } } } }`, nil, true},
+		{"introspection", `{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name } } } } } } } }`, nil, false},
+		{"syntax error", `{ videos {`, nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkLimits(Request{Query: test.query, Variables: test.variables})
+			if rejected := err != nil; rejected != test.rejected {
+				t.Errorf("checkLimits rejected: %v (%v), want %v", rejected, err, test.rejected)
+			}
+		})
+	}
+}
+
+func TestSearchQueryLength(t *testing.T) {
+	query := `query($q: String!) { search(query: $q) { totalCount } }`
+	result := Execute(context.Background(), Request{Query: query, Variables: map[string]interface{}{"q": strings.Repeat("a", 501)}})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "between 1 and 500 characters") {
+		t.Errorf("Execute returned errors %v, want the query length error", result.Errors)
+	}
+}
+
+func TestIsQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		isQuery       bool
+	}{
+		{"shorthand query", `{ videos { totalCount } }`, "", true},
+		{"named query", `query Latest { videos { totalCount } }`, "", true},
+		{"mutation", `mutation { deleteApiKey(id: "1") }`, "", false},
+		{"query selected next to a mutation", `query Latest { videos { totalCount } } mutation Delete { deleteApiKey(id: "1") }`, "Latest", true},
+		{"mutation selected next to a query", `query Latest { videos { totalCount } } mutation Delete { deleteApiKey(id: "1") }`, "Delete", false},
+		{"mutation among operations without name", `query Latest { videos { totalCount } } mutation Delete { deleteApiKey(id: "1") }`, "", false},
+		{"syntax error", `mutation {`, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsQuery(Request{Query: test.query, OperationName: test.operationName}); got != test.isQuery {
+				t.Errorf("IsQuery(%q) = %v, want %v", test.query, got, test.isQuery)
+			}
+		})
+	}
+}
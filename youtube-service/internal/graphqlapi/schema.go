@@ -0,0 +1,410 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/models-services/add_key"
+	"github.com/youtube-service/models-services/get_video-search_video"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+const (
+	defaultIngestionRuns = 20
+	maxIngestionRuns     = 100
+	// Same limit as the query param of the search endpoint
+	maxSearchQueryLength = 500
+)
+
+var errAdminOnly = errors.New("admin token required")
+
+type adminKey struct{}
+
+// Returns a context marking whether the request was made with the admin token
+func WithAdmin(ctx context.Context, admin bool) context.Context {
+	return context.WithValue(ctx, adminKey{}, admin)
+}
+
+func requireAdmin(p graphql.ResolveParams) error {
+	if admin, _ := p.Context.Value(adminKey{}).(bool); !admin {
+		return errAdminOnly
+	}
+	return nil
+}
+
+// Request is the body of a GraphQL request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+var (
+	schema     graphql.Schema
+	schemaOnce sync.Once
+)
+
+// Executes a GraphQL request against the schema of the stored videos, ingestion runs and API keys.
+// Key management is only allowed when ctx was marked as admin with WithAdmin.
+// Requests nested too deep or too complex are rejected before any field is resolved.
+func Execute(ctx context.Context, req Request) *graphql.Result {
+	schemaOnce.Do(func() {
+		var err error
+		schema, err = newSchema()
+		if err != nil {
+			log.Fatalf("Execute: Error creating GraphQL schema: %v", err)
+		}
+	})
+	if err := checkLimits(req); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())}}
+	}
+	return graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        ctx,
+	})
+}
+
+func newSchema() (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"videos": {
+				Type:        graphql.NewNonNull(videoConnectionType),
+				Description: "Stored videos, latest published first by default",
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"filter": {Type: videoFilterInput},
+				}),
+				Resolve: resolveVideos,
+			},
+			"search": {
+				Type:        graphql.NewNonNull(searchConnectionType),
+				Description: "Stored videos matching a search query, most relevant first by default",
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"query":  {Type: graphql.NewNonNull(graphql.String)},
+					"lang":   {Type: graphql.String, Description: "Language code of the query, or none to search without stemming"},
+					"filter": {Type: videoFilterInput},
+					"facets": {Type: graphql.NewList(graphql.NewNonNull(facetEnum))},
+				}),
+				Resolve: resolveSearch,
+			},
+			"trending": {
+				Type:        graphql.NewNonNull(videoConnectionType),
+				Description: "Videos whose views and likes grew fastest over the trending window",
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: videoFilterInput},
+					"first":  {Type: graphql.Int},
+					"after":  {Type: graphql.String},
+				},
+				Resolve: resolveTrending,
+			},
+			"video": {
+				Type: videoType,
+				Args: graphql.FieldConfigArgument{
+					"uniqueId": {Type: graphql.NewNonNull(graphql.ID), Description: "YouTube id of the video"},
+				},
+				Resolve: resolveVideo,
+			},
+			"channel": {
+				Type: channelType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: resolveChannel,
+			},
+			"ingestionRuns": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ingestionRunType))),
+				Description: "Latest runs of the job fetching videos, most recent first",
+				Args: graphql.FieldConfigArgument{
+					"first": {Type: graphql.Int, DefaultValue: defaultIngestionRuns},
+				},
+				Resolve: resolveIngestionRuns,
+			},
+			"apiKeys": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(apiKeyType))),
+				Description: "Admin only",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := requireAdmin(p); err != nil {
+						return nil, err
+					}
+					keys, err := add_key.GetKeys()
+					if err != nil {
+						return nil, errors.New("failed to fetch api keys")
+					}
+					return keys, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"addApiKey": {
+				Type:        graphql.NewNonNull(apiKeyType),
+				Description: "Admin only. Adds a key after checking it with the YouTube API.",
+				Args: graphql.FieldConfigArgument{
+					"key": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolveAddApiKey,
+			},
+			"setApiKeyExpired": {
+				Type:        graphql.NewNonNull(apiKeyType),
+				Description: "Admin only. Expired keys are not used until they are checked again.",
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: graphql.NewNonNull(graphql.ID)},
+					"expired": {Type: graphql.NewNonNull(graphql.Boolean)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := requireAdmin(p); err != nil {
+						return nil, err
+					}
+					key, err := add_key.SetKeyExpiration(p.Args["id"].(string), p.Args["expired"].(bool))
+					return key, keyError(err)
+				},
+			},
+			"deleteApiKey": {
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "Admin only",
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := requireAdmin(p); err != nil {
+						return nil, err
+					}
+					if err := add_key.DeleteKey(p.Args["id"].(string)); err != nil {
+						return nil, keyError(err)
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// Adds the first, after and sort arguments of paginated fields to args
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	if args == nil {
+		args = graphql.FieldConfigArgument{}
+	}
+	args["first"] = &graphql.ArgumentConfig{Type: graphql.Int, Description: "Number of videos per page"}
+	args["after"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "endCursor of the previous page"}
+	args["sort"] = &graphql.ArgumentConfig{Type: videoSortEnum}
+	return args
+}
+
+func pageRequest(p graphql.ResolveParams) (entities.PageRequest, error) {
+	req := entities.PageRequest{
+		Page:                 1,
+		PerPage:              configs.GetPerPageLimit(),
+		RecencyHalfLifeHours: float64(configs.GetRecencyHalfLifeHours()),
+	}
+	if first, ok := p.Args["first"].(int); ok {
+		if first < 1 || int64(first) > configs.GetMaxPerPageLimit() {
+			return req, fmt.Errorf("first must be between 1 and %d", configs.GetMaxPerPageLimit())
+		}
+		req.PerPage = int64(first)
+	}
+	req.Cursor, _ = p.Args["after"].(string)
+	req.Sort, _ = p.Args["sort"].(string)
+	return req, nil
+}
+
+func videoFilter(p graphql.ResolveParams) entities.VideoFilter {
+	var filter entities.VideoFilter
+	args, _ := p.Args["filter"].(map[string]interface{})
+	if t, ok := args["publishedAfter"].(time.Time); ok {
+		filter.PublishedAfter = &t
+	}
+	if t, ok := args["publishedBefore"].(time.Time); ok {
+		filter.PublishedBefore = &t
+	}
+	filter.ChannelId, _ = args["channelId"].(string)
+	filter.SourceQuery, _ = args["topic"].(string)
+	filter.DurationBucket, _ = args["duration"].(string)
+	filter.LiveBroadcastContent, _ = args["live"].(string)
+	filter.MinViewCount, _ = args["minViews"].(int64)
+	filter.DefaultLanguage, _ = args["language"].(string)
+	return filter
+}
+
+// Returns the error of a failed video query to expose to clients
+func videosError(err error) error {
+	switch {
+	case errors.Is(err, get_video-search_video.ErrInvalidCursor):
+		return errors.New("after is not a valid cursor")
+	case errors.Is(err, get_video-search_video.ErrInvalidSort):
+		return errors.New("sort is not supported by this field")
+	default:
+		return errors.New("failed to fetch videos")
+	}
+}
+
+// Returns the error of a failed key operation to expose to clients
+func keyError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, add_key.ErrKeyNotFound):
+		return err
+	default:
+		return errors.New("failed to update api key")
+	}
+}
+
+func connection(page entities.VideoPage) map[string]interface{} {
+	pageInfo := map[string]interface{}{"hasNextPage": page.HasMore}
+	if page.NextCursor != "" {
+		pageInfo["endCursor"] = page.NextCursor
+	}
+	return map[string]interface{}{
+		"nodes":      page.Videos,
+		"totalCount": page.Total,
+		"pageInfo":   pageInfo,
+	}
+}
+
+func resolveVideos(p graphql.ResolveParams) (interface{}, error) {
+	req, err := pageRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	page, err := get_video-search_video.GetVideos(req, videoFilter(p))
+	if err != nil {
+		return nil, videosError(err)
+	}
+	return connection(page), nil
+}
+
+func resolveSearch(p graphql.ResolveParams) (interface{}, error) {
+	req, err := pageRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	text := p.Args["query"].(string)
+	if utf8.RuneCountInString(text) > maxSearchQueryLength {
+		return nil, fmt.Errorf("query must be between 1 and %d characters", maxSearchQueryLength)
+	}
+	query, err := searchquery.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	query.Language, _ = p.Args["lang"].(string)
+
+	facets, _ := p.Args["facets"].([]interface{})
+	for _, facet := range facets {
+		req.Facets = append(req.Facets, facet.(string))
+	}
+	req.HighlightPreTag = configs.GetHighlightPreTag()
+	req.HighlightPostTag = configs.GetHighlightPostTag()
+
+	page, err := get_video-search_video.SearchVideos(query, req, videoFilter(p))
+	if err != nil {
+		return nil, videosError(err)
+	}
+
+	result := connection(page)
+	result["didYouMean"] = page.DidYouMean
+	if page.CorrectedQuery != "" {
+		result["correctedQuery"] = page.CorrectedQuery
+	}
+	counts := make([]map[string]interface{}, 0, len(req.Facets))
+	for _, facet := range req.Facets {
+		counts = append(counts, map[string]interface{}{"name": facet, "values": page.Facets[facet]})
+	}
+	result["facets"] = counts
+	return result, nil
+}
+
+func resolveTrending(p graphql.ResolveParams) (interface{}, error) {
+	req, err := pageRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	page, err := get_video-search_video.GetTrendingVideos(req, videoFilter(p))
+	if err != nil {
+		return nil, videosError(err)
+	}
+	return connection(page), nil
+}
+
+func resolveVideo(p graphql.ResolveParams) (interface{}, error) {
+	video, err := get_video-search_video.GetVideo(p.Args["uniqueId"].(string))
+	if errors.Is(err, get_video-search_video.ErrVideoNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("failed to fetch video")
+	}
+	return video, nil
+}
+
+// Returns the channel with the given id if it has stored videos
+func resolveChannel(p graphql.ResolveParams) (interface{}, error) {
+	req := entities.PageRequest{Page: 1, PerPage: 1}
+	page, err := get_video-search_video.GetVideos(req, entities.VideoFilter{ChannelId: p.Args["id"].(string)})
+	if err != nil {
+		return nil, videosError(err)
+	}
+	if len(page.Videos) == 0 {
+		return nil, nil
+	}
+	return channel{Id: page.Videos[0].ChannelId, Title: page.Videos[0].ChannelTitle}, nil
+}
+
+func resolveChannelVideos(p graphql.ResolveParams) (interface{}, error) {
+	req, err := pageRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	filter := entities.VideoFilter{ChannelId: p.Source.(channel).Id}
+	page, err := get_video-search_video.GetVideos(req, filter)
+	if err != nil {
+		return nil, videosError(err)
+	}
+	return connection(page), nil
+}
+
+func resolveIngestionRuns(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > maxIngestionRuns {
+		return nil, fmt.Errorf("first must be between 1 and %d", maxIngestionRuns)
+	}
+	runs, err := get_video-search_video.GetIngestionRuns(int64(first))
+	if err != nil {
+		return nil, errors.New("failed to fetch ingestion runs")
+	}
+	return runs, nil
+}
+
+func resolveAddApiKey(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireAdmin(p); err != nil {
+		return nil, err
+	}
+	key := p.Args["key"].(string)
+	if !add_key.IsKeyValid(key) {
+		return nil, errors.New("invalid api key")
+	}
+	apiKey, err := add_key.InsertKey(key)
+	if err != nil {
+		return nil, errors.New("failed to insert api key into the database")
+	}
+	return apiKey, nil
+}
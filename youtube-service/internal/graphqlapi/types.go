@@ -0,0 +1,262 @@
+package graphqlapi
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/models-services/add_key"
+)
+
+// Int64 is an integer scalar for counts which can exceed the 32 bits of the GraphQL Int
+var Int64 = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Int64",
+	Description: "64 bit integer",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case int64:
+			return v
+		case *int64:
+			if v == nil {
+				return nil
+			}
+			return *v
+		case int:
+			return int64(v)
+		default:
+			return nil
+		}
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case int:
+			return int64(v)
+		case int64:
+			return v
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v)
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.IntValue); ok {
+			if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+				return n
+			}
+		}
+		return nil
+	},
+})
+
+var durationEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "Duration",
+	Values: graphql.EnumValueConfigMap{
+		"SHORT":  {Value: entities.DurationShort, Description: "Less than 4 minutes"},
+		"MEDIUM": {Value: entities.DurationMedium, Description: "4 to 20 minutes"},
+		"LONG":   {Value: entities.DurationLong, Description: "More than 20 minutes"},
+	},
+})
+
+var liveBroadcastEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "LiveBroadcast",
+	Values: graphql.EnumValueConfigMap{
+		"NONE":     {Value: "none"},
+		"LIVE":     {Value: "live"},
+		"UPCOMING": {Value: "upcoming"},
+	},
+})
+
+var videoSortEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "VideoSort",
+	Values: graphql.EnumValueConfigMap{
+		"RELEVANCE": {Value: entities.SortRelevance, Description: "Only for searches"},
+		"NEWEST":    {Value: entities.SortNewest},
+		"OLDEST":    {Value: entities.SortOldest},
+		"VIEWS":     {Value: entities.SortViews},
+		"LIKES":     {Value: entities.SortLikes},
+	},
+})
+
+var facetEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "FacetName",
+	Values: graphql.EnumValueConfigMap{
+		"CHANNEL":  {Value: entities.FacetChannel},
+		"MONTH":    {Value: entities.FacetMonth},
+		"DURATION": {Value: entities.FacetDuration},
+		"TOPIC":    {Value: entities.FacetTopic},
+	},
+})
+
+var videoFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "VideoFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"publishedAfter":  {Type: graphql.DateTime},
+		"publishedBefore": {Type: graphql.DateTime},
+		"channelId":       {Type: graphql.String},
+		"topic":           {Type: graphql.String, Description: "Query the videos were fetched for"},
+		"duration":        {Type: durationEnum},
+		"live":            {Type: liveBroadcastEnum},
+		"minViews":        {Type: Int64},
+		"language":        {Type: graphql.String},
+	},
+})
+
+var trendingType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Trending",
+	Fields: graphql.Fields{
+		"viewVelocity": {Type: graphql.NewNonNull(graphql.Float), Description: "Views gained per hour"},
+		"likeVelocity": {Type: graphql.NewNonNull(graphql.Float), Description: "Likes gained per hour"},
+		"score":        {Type: graphql.NewNonNull(graphql.Float)},
+		"computedAt":   {Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var highlightsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Highlights",
+	Fields: graphql.Fields{
+		"title":       {Type: graphql.NewNonNull(graphql.String)},
+		"description": {Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage": {Type: graphql.NewNonNull(graphql.Boolean)},
+		"endCursor":   {Type: graphql.String, Description: "Passed as after to fetch the next page"},
+	},
+})
+
+var facetCountType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "FacetCount",
+	Fields: graphql.Fields{
+		"value": {Type: graphql.NewNonNull(graphql.String)},
+		"label": {Type: graphql.String},
+		"count": {Type: graphql.NewNonNull(Int64)},
+	},
+})
+
+var facetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Facet",
+	Fields: graphql.Fields{
+		"name":   {Type: graphql.NewNonNull(facetEnum)},
+		"values": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(facetCountType)))},
+	},
+})
+
+var apiKeyType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ApiKey",
+	Description: "YouTube Data API key. Only its last 4 characters are exposed.",
+	Fields: graphql.Fields{
+		"id": {Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(entities.ApiKey).Id, nil
+		}},
+		"key": {Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return add_key.MaskKey(p.Source.(entities.ApiKey).Key), nil
+		}},
+		"isExpired":   {Type: graphql.NewNonNull(graphql.Boolean)},
+		"lastUpdated": {Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var ingestionRunType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "IngestionRun",
+	Description: "Run of the job fetching the latest videos from YouTube",
+	Fields: graphql.Fields{
+		"id": {Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(entities.IngestionRun).Id, nil
+		}},
+		"query":      {Type: graphql.NewNonNull(graphql.String)},
+		"startedAt":  {Type: graphql.NewNonNull(graphql.DateTime)},
+		"finishedAt": {Type: graphql.NewNonNull(graphql.DateTime)},
+		"status":     {Type: graphql.NewNonNull(graphql.String), Description: "succeeded, skipped when the results did not change, or failed"},
+		"fetched":    {Type: graphql.NewNonNull(Int64)},
+		"inserted":   {Type: graphql.NewNonNull(Int64), Description: "Fetched videos which were not stored yet"},
+		"error":      {Type: graphql.String},
+	},
+})
+
+// Channel of stored videos
+type channel struct {
+	Id    string
+	Title string
+}
+
+// videoType, channelType and the connections reference each other, so their fields are thunks
+var (
+	videoType            *graphql.Object
+	channelType          *graphql.Object
+	videoConnectionType  *graphql.Object
+	searchConnectionType *graphql.Object
+)
+
+func init() {
+	videoType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Video",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": {Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(entities.Video).Id, nil
+				}},
+				"uniqueId":    {Type: graphql.NewNonNull(graphql.ID), Description: "YouTube id of the video"},
+				"title":       {Type: graphql.NewNonNull(graphql.String)},
+				"description": {Type: graphql.NewNonNull(graphql.String)},
+				"publishedAt": {Type: graphql.NewNonNull(graphql.DateTime)},
+				"channel": {Type: graphql.NewNonNull(channelType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					video := p.Source.(entities.Video)
+					return channel{Id: video.ChannelId, Title: video.ChannelTitle}, nil
+				}},
+				"sourceQuery":          {Type: graphql.NewNonNull(graphql.String)},
+				"durationSeconds":      {Type: graphql.NewNonNull(Int64)},
+				"durationBucket":       {Type: durationEnum},
+				"viewCount":            {Type: graphql.NewNonNull(Int64)},
+				"likeCount":            {Type: graphql.NewNonNull(Int64)},
+				"defaultLanguage":      {Type: graphql.NewNonNull(graphql.String)},
+				"liveBroadcastContent": {Type: graphql.NewNonNull(graphql.String)},
+				"tags":                 {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				"trending":             {Type: trendingType},
+				"highlights":           {Type: highlightsType, Description: "Only set for search results"},
+			}
+		}),
+	})
+
+	channelType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Channel",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":    {Type: graphql.NewNonNull(graphql.ID)},
+				"title": {Type: graphql.NewNonNull(graphql.String)},
+				"videos": {
+					Type:    graphql.NewNonNull(videoConnectionType),
+					Args:    pageArgs(nil),
+					Resolve: resolveChannelVideos,
+				},
+			}
+		}),
+	})
+
+	videoConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "VideoConnection",
+		Fields: graphql.Fields{
+			"nodes":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(videoType)))},
+			"totalCount": {Type: graphql.NewNonNull(Int64)},
+			"pageInfo":   {Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	searchConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchConnection",
+		Fields: graphql.Fields{
+			"nodes":          {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(videoType)))},
+			"totalCount":     {Type: graphql.NewNonNull(Int64)},
+			"pageInfo":       {Type: graphql.NewNonNull(pageInfoType)},
+			"didYouMean":     {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Corrections of the query when it has few results"},
+			"correctedQuery": {Type: graphql.String, Description: "Set when the results are those of the closest correction of the query"},
+			"facets":         {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(facetType))), Description: "Counts of the requested facets"},
+		},
+	})
+}
@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/models-services/add_key"
+	"github.com/youtube-service/pkg/youtubepb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var sorts = map[youtubepb.VideoSort]string{
+	youtubepb.VideoSort_VIDEO_SORT_UNSPECIFIED: "",
+	youtubepb.VideoSort_VIDEO_SORT_RELEVANCE:   entities.SortRelevance,
+	youtubepb.VideoSort_VIDEO_SORT_NEWEST:      entities.SortNewest,
+	youtubepb.VideoSort_VIDEO_SORT_OLDEST:      entities.SortOldest,
+	youtubepb.VideoSort_VIDEO_SORT_VIEWS:       entities.SortViews,
+	youtubepb.VideoSort_VIDEO_SORT_LIKES:       entities.SortLikes,
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func videoToProto(video entities.Video) *youtubepb.Video {
+	v := &youtubepb.Video{
+		Id:                   video.Id,
+		UniqueId:             video.UniqueId,
+		Title:                video.Title,
+		Description:          video.Description,
+		PublishedAt:          timestamp(video.PublishedAt),
+		ChannelId:            video.ChannelId,
+		ChannelTitle:         video.ChannelTitle,
+		SourceQuery:          video.SourceQuery,
+		DurationSeconds:      video.DurationSeconds,
+		DurationBucket:       video.DurationBucket,
+		ViewCount:            video.ViewCount,
+		LikeCount:            video.LikeCount,
+		DefaultLanguage:      video.DefaultLanguage,
+		LiveBroadcastContent: video.LiveBroadcastContent,
+		Tags:                 video.Tags,
+	}
+	if video.Trending != nil {
+		v.Trending = &youtubepb.Trending{
+			ViewVelocity: video.Trending.ViewVelocity,
+			LikeVelocity: video.Trending.LikeVelocity,
+			Score:        video.Trending.Score,
+			ComputedAt:   timestamp(video.Trending.ComputedAt),
+		}
+	}
+	if video.Highlights != nil {
+		v.Highlights = &youtubepb.Highlights{
+			Title:       video.Highlights.Title,
+			Description: video.Highlights.Description,
+		}
+	}
+	return v
+}
+
+func videosToProto(videos []entities.Video) []*youtubepb.Video {
+	res := make([]*youtubepb.Video, 0, len(videos))
+	for _, video := range videos {
+		res = append(res, videoToProto(video))
+	}
+	return res
+}
+
+func apiKeyToProto(key entities.ApiKey) *youtubepb.ApiKey {
+	return &youtubepb.ApiKey{
+		Id:          key.Id,
+		MaskedKey:   add_key.MaskKey(key.Key),
+		IsExpired:   key.IsExpired,
+		LastUpdated: timestamp(key.LastUpdated),
+	}
+}
+
+func filterFromProto(filter *youtubepb.VideoFilter) entities.VideoFilter {
+	var videoFilter entities.VideoFilter
+	if filter == nil {
+		return videoFilter
+	}
+	if filter.PublishedAfter != nil {
+		t := filter.PublishedAfter.AsTime()
+		videoFilter.PublishedAfter = &t
+	}
+	if filter.PublishedBefore != nil {
+		t := filter.PublishedBefore.AsTime()
+		videoFilter.PublishedBefore = &t
+	}
+	videoFilter.ChannelId = filter.ChannelId
+	videoFilter.SourceQuery = filter.Topic
+	videoFilter.DurationBucket = filter.Duration
+	videoFilter.LiveBroadcastContent = filter.Live
+	videoFilter.MinViewCount = filter.MinViews
+	videoFilter.DefaultLanguage = filter.Language
+	return videoFilter
+}
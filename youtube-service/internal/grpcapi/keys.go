@@ -0,0 +1,64 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/youtube-service/models-services/add_key"
+	"github.com/youtube-service/pkg/youtubepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Calls are authorized by authorizeAdmin
+type keyAdminServer struct {
+	youtubepb.UnimplementedKeyAdminServiceServer
+}
+
+func (s *keyAdminServer) ListApiKeys(ctx context.Context, req *youtubepb.ListApiKeysRequest) (*youtubepb.ListApiKeysResponse, error) {
+	keys, err := add_key.GetKeys()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to fetch api keys")
+	}
+	res := &youtubepb.ListApiKeysResponse{}
+	for _, key := range keys {
+		res.ApiKeys = append(res.ApiKeys, apiKeyToProto(key))
+	}
+	return res, nil
+}
+
+func (s *keyAdminServer) AddApiKey(ctx context.Context, req *youtubepb.AddApiKeyRequest) (*youtubepb.ApiKey, error) {
+	if req.Key == "" {
+		return nil, invalidArgument("key", "is required")
+	}
+	if !add_key.IsKeyValid(req.Key) {
+		return nil, invalidArgument("key", "is not a valid YouTube Data API key")
+	}
+	key, err := add_key.InsertKey(req.Key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to insert api key into the database")
+	}
+	return apiKeyToProto(key), nil
+}
+
+func (s *keyAdminServer) SetApiKeyExpired(ctx context.Context, req *youtubepb.SetApiKeyExpiredRequest) (*youtubepb.ApiKey, error) {
+	key, err := add_key.SetKeyExpiration(req.Id, req.Expired)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	return apiKeyToProto(key), nil
+}
+
+func (s *keyAdminServer) DeleteApiKey(ctx context.Context, req *youtubepb.DeleteApiKeyRequest) (*youtubepb.DeleteApiKeyResponse, error) {
+	if err := add_key.DeleteKey(req.Id); err != nil {
+		return nil, keyError(err)
+	}
+	return &youtubepb.DeleteApiKeyResponse{}, nil
+}
+
+func keyError(err error) error {
+	if errors.Is(err, add_key.ErrKeyNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to update api key")
+}
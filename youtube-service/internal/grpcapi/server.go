@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/pkg/youtubepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Methods of services requiring the admin token start with this prefix
+var adminServicePrefix = "/" + youtubepb.KeyAdminService_ServiceDesc.ServiceName + "/"
+
+// Serves the gRPC API on the given address until the listener fails
+func Serve(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(authorizeAdmin))
+	youtubepb.RegisterVideoServiceServer(server, &videoServer{})
+	youtubepb.RegisterKeyAdminServiceServer(server, &keyAdminServer{})
+	// Lets clients such as grpcurl list the services without the proto files
+	reflection.Register(server)
+
+	log.Infof("Serve: Starting gRPC server on %v", address)
+	return server.Serve(listener)
+}
+
+// Rejects calls to admin services which don't bear the admin token in the authorization metadata
+func authorizeAdmin(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !strings.HasPrefix(info.FullMethod, adminServicePrefix) {
+		return handler(ctx, req)
+	}
+
+	token := configs.GetAdminToken()
+	if token == "" {
+		return nil, status.Error(codes.PermissionDenied, "admin operations are disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, authorization := range md.Get("authorization") {
+		bearer := strings.TrimPrefix(authorization, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+			return handler(ctx, req)
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "admin token required")
+}
+
+func invalidArgument(field, message string) error {
+	return status.Errorf(codes.InvalidArgument, "%s %s", field, message)
+}
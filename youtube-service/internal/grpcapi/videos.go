@@ -0,0 +1,183 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/pubsub"
+	"github.com/youtube-service/models-services/get_video-search_video"
+	"github.com/youtube-service/pkg/searchquery"
+	"github.com/youtube-service/pkg/youtubepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var videoIdRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+type videoServer struct {
+	youtubepb.UnimplementedVideoServiceServer
+}
+
+func (s *videoServer) ListVideos(ctx context.Context, req *youtubepb.ListVideosRequest) (*youtubepb.ListVideosResponse, error) {
+	pageReq, err := pageRequest(req.PageSize, req.PageToken, req.Sort)
+	if err != nil {
+		return nil, err
+	}
+	videoFilter, err := filter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := get_video-search_video.GetVideos(pageReq, videoFilter)
+	if err != nil {
+		return nil, videosError(err, "failed to fetch videos")
+	}
+	return &youtubepb.ListVideosResponse{
+		Videos:        videosToProto(page.Videos),
+		Total:         page.Total,
+		NextPageToken: page.NextCursor,
+	}, nil
+}
+
+// Searches are not recorded for suggestions, which reflect what users of the HTTP API type
+func (s *videoServer) SearchVideos(ctx context.Context, req *youtubepb.SearchVideosRequest) (*youtubepb.SearchVideosResponse, error) {
+	// Characters are counted like by the max=500 rule of the HTTP API
+	if req.Query == "" || utf8.RuneCountInString(req.Query) > 500 {
+		return nil, invalidArgument("query", "must be between 1 and 500 characters")
+	}
+	query, err := searchquery.Parse(req.Query)
+	if err != nil {
+		return nil, invalidArgument("query", "must be a valid search query, "+err.Error())
+	}
+	query.Language = req.Lang
+
+	pageReq, err := pageRequest(req.PageSize, req.PageToken, req.Sort)
+	if err != nil {
+		return nil, err
+	}
+	pageReq.HighlightPreTag = configs.GetHighlightPreTag()
+	pageReq.HighlightPostTag = configs.GetHighlightPostTag()
+	for _, facet := range req.Facets {
+		switch facet {
+		case entities.FacetChannel, entities.FacetMonth, entities.FacetDuration, entities.FacetTopic:
+			pageReq.Facets = append(pageReq.Facets, facet)
+		default:
+			return nil, invalidArgument("facets", "must be channel, month, duration or topic")
+		}
+	}
+	videoFilter, err := filter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := get_video-search_video.SearchVideos(query, pageReq, videoFilter)
+	if err != nil {
+		return nil, videosError(err, "failed to search videos")
+	}
+
+	res := &youtubepb.SearchVideosResponse{
+		Videos:         videosToProto(page.Videos),
+		Total:          page.Total,
+		NextPageToken:  page.NextCursor,
+		DidYouMean:     page.DidYouMean,
+		CorrectedQuery: page.CorrectedQuery,
+	}
+	for _, name := range pageReq.Facets {
+		facet := &youtubepb.Facet{Name: name}
+		for _, count := range page.Facets[name] {
+			facet.Values = append(facet.Values, &youtubepb.FacetCount{Value: count.Value, Label: count.Label, Count: count.Count})
+		}
+		res.Facets = append(res.Facets, facet)
+	}
+	return res, nil
+}
+
+func (s *videoServer) GetVideo(ctx context.Context, req *youtubepb.GetVideoRequest) (*youtubepb.Video, error) {
+	if !videoIdRegex.MatchString(req.UniqueId) {
+		return nil, invalidArgument("unique_id", "must be a YouTube video id")
+	}
+	video, err := get_video-search_video.GetVideo(req.UniqueId)
+	if errors.Is(err, get_video-search_video.ErrVideoNotFound) {
+		return nil, status.Error(codes.NotFound, "video not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to fetch video")
+	}
+	return videoToProto(video), nil
+}
+
+// Streams videos as they are inserted, after replaying those inserted after after_id
+func (s *videoServer) WatchNewVideos(req *youtubepb.WatchNewVideosRequest, stream youtubepb.VideoService_WatchNewVideosServer) error {
+	videoFilter := entities.VideoFilter{ChannelId: req.ChannelId, SourceQuery: req.Topic}
+
+	err := get_video-search_video.WatchNewVideos(stream.Context(), req.AfterId, videoFilter, func(video entities.Video) error {
+		return stream.Send(videoToProto(video))
+	})
+	switch {
+	case errors.Is(err, get_video-search_video.ErrInvalidCursor):
+		return invalidArgument("after_id", "must be the id of a video")
+	case errors.Is(err, pubsub.ErrSlowSubscriber):
+		return status.Error(codes.Unavailable, "stream fell behind inserted videos, resume it with the id of the last received video as after_id")
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
+
+func pageRequest(pageSize int64, pageToken string, sort youtubepb.VideoSort) (entities.PageRequest, error) {
+	req := entities.PageRequest{
+		Page:                 1,
+		PerPage:              configs.GetPerPageLimit(),
+		Cursor:               pageToken,
+		RecencyHalfLifeHours: float64(configs.GetRecencyHalfLifeHours()),
+	}
+	if pageSize != 0 {
+		if pageSize < 1 || pageSize > configs.GetMaxPerPageLimit() {
+			return req, invalidArgument("page_size", fmt.Sprintf("must be between 1 and %d", configs.GetMaxPerPageLimit()))
+		}
+		req.PerPage = pageSize
+	}
+	var ok bool
+	if req.Sort, ok = sorts[sort]; !ok {
+		return req, invalidArgument("sort", "is not supported")
+	}
+	return req, nil
+}
+
+func filter(filter *youtubepb.VideoFilter) (entities.VideoFilter, error) {
+	videoFilter := filterFromProto(filter)
+	switch videoFilter.DurationBucket {
+	case "", entities.DurationShort, entities.DurationMedium, entities.DurationLong:
+	default:
+		return videoFilter, invalidArgument("filter.duration", "must be short, medium or long")
+	}
+	switch videoFilter.LiveBroadcastContent {
+	case "", "none", "live", "upcoming":
+	default:
+		return videoFilter, invalidArgument("filter.live", "must be none, live or upcoming")
+	}
+	if videoFilter.MinViewCount < 0 {
+		return videoFilter, invalidArgument("filter.min_views", "must not be negative")
+	}
+	if videoFilter.PublishedAfter != nil && videoFilter.PublishedBefore != nil && !videoFilter.PublishedAfter.Before(*videoFilter.PublishedBefore) {
+		return videoFilter, invalidArgument("filter.published_before", "must be after published_after")
+	}
+	return videoFilter, nil
+}
+
+// Returns the status of a failed video query to expose to clients
+func videosError(err error, message string) error {
+	switch {
+	case errors.Is(err, get_video-search_video.ErrInvalidCursor):
+		return invalidArgument("page_token", "is invalid")
+	case errors.Is(err, get_video-search_video.ErrInvalidSort):
+		return invalidArgument("sort", "is not supported by this method")
+	default:
+		return status.Error(codes.Internal, message)
+	}
+}
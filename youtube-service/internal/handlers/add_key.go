@@ -5,26 +5,24 @@ import (
 	"github.com/youtube-service/internal/models-services"
 )
 
+type addKeyRequest struct {
+	Key string `query:"key" validate:"required,api_key"`
+}
+
 // add_key handler adds a new API key to the database if it is valid
 func Do(c *fiber.Ctx) error {
-	apiKey := c.Query("key", "")
-	if apiKey == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "api_key query param is required",
-		})
+	var params addKeyRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
 	}
 
-	if !add_key.IsKeyValid(apiKey) {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "invalid api key",
-		})
+	if !add_key.IsKeyValid(params.Key) {
+		return errorResponse(c, fiber.StatusUnauthorized, "invalid api key")
 	}
 
-	err := add_key.InsertKey(apiKey)
+	_, err := add_key.InsertKey(params.Key)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to insert api key into the database",
-		})
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to insert api key into the database")
 	}
 	return c.JSON(fiber.Map{
 		"message": "api key added successfully",
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
+)
+
+// Reports whether the request bears the admin token, never when no token is configured
+func isAdmin(c *fiber.Ctx) bool {
+	token := configs.GetAdminToken()
+	if token == "" {
+		return false
+	}
+	bearer := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+// RequireAdmin rejects requests which don't bear the admin token in the Authorization header
+func RequireAdmin(c *fiber.Ctx) error {
+	if configs.GetAdminToken() == "" {
+		return errorResponse(c, fiber.StatusForbidden, "admin operations are disabled")
+	}
+	if !isAdmin(c) {
+		return errorResponse(c, fiber.StatusUnauthorized, "admin token required")
+	}
+	return c.Next()
+}
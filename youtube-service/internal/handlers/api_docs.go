@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/openapi"
+)
+
+// api_docs handler returns the Swagger UI page of the OpenAPI document
+func Do(c *fiber.Ctx) error {
+	c.Type("html")
+	return c.Send(openapi.SwaggerUI)
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/openapi"
+)
+
+// api_docs_asset handler returns a stylesheet or script of the Swagger UI page, which are versioned
+// with the service so they can be cached for a day
+func Do(c *fiber.Ctx) error {
+	name := c.Params("asset")
+	content, ok := openapi.SwaggerUIAsset(name)
+	if !ok {
+		return errorResponse(c, fiber.StatusNotFound, "asset not found")
+	}
+	c.Type(filepath.Ext(name))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return c.Send(content)
+}
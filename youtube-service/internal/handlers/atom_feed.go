@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/feeds"
+)
+
+// atom_feed handler returns the newest stored videos of the topic as an Atom feed
+func Do(c *fiber.Ctx) error {
+	return sendFeed(c, "application/atom+xml; charset=utf-8", feeds.Atom)
+}
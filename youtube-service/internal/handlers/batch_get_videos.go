@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type batchGetRequest struct {
+	Ids []string `json:"ids" validate:"required,min=1,batch_ids,dive,video_id"`
+}
+
+// batch_get_videos handler returns the stored videos with the given YouTube ids along with the ids
+// which aren't stored
+func Do(c *fiber.Ctx) error {
+	var params batchGetRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	videos, notFound, err := models-services.(get_video-search_video).BatchGetVideos(params.Ids)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch videos")
+	}
+
+	return c.JSON(fiber.Map{
+		"videos":    videos,
+		"not_found": notFound,
+	})
+}
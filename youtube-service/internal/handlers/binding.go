@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
+)
+
+var (
+	languageTagRegex = regexp.MustCompile(`^([a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})*|none)$`)
+	videoIdRegex     = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	apiKeyRegex      = regexp.MustCompile(`^AIza[0-9A-Za-z_-]{35}$`)
+	objectIdRegex    = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+	// Cursors are encoded with the unpadded URL safe base64 alphabet
+	cursorRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Validates the request structs. Fields are reported by the name of the param they are bound from.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(paramName)
+	v.RegisterValidation("language_tag", func(fl validator.FieldLevel) bool {
+		return languageTagRegex.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("video_id", func(fl validator.FieldLevel) bool {
+		return videoIdRegex.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("api_key", func(fl validator.FieldLevel) bool {
+		return apiKeyRegex.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("object_id", func(fl validator.FieldLevel) bool {
+		return objectIdRegex.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("cursor", func(fl validator.FieldLevel) bool {
+		return cursorRegex.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("webhook_url", func(fl validator.FieldLevel) bool {
+		u, err := url.Parse(fl.Field().String())
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	})
+	v.RegisterValidation("per_page", func(fl validator.FieldLevel) bool {
+		perPage := fl.Field().Int()
+		return perPage >= 1 && perPage <= configs.GetMaxPerPageLimit()
+	})
+	v.RegisterValidation("batch_ids", func(fl validator.FieldLevel) bool {
+		return int64(fl.Field().Len()) <= configs.GetMaxBatchGetIds()
+	})
+	v.RegisterStructValidation(validateVideoFilterQuery, videoFilterQuery{})
+	v.RegisterStructValidation(validateCreateAlertRuleRequest, createAlertRuleRequest{})
+	return v
+}
+
+// Returns the name of the path param, query param, header or JSON property a struct field is bound from
+func paramName(field reflect.StructField) string {
+	for _, tag := range []string{"params", "query", "reqHeader", "json"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// A request param failing validation
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Returned by bind when params cannot be parsed or fail validation
+type validationError struct {
+	Fields []fieldError
+}
+
+func (e *validationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+" "+field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Returns the validation error of a single param found invalid after binding
+func invalidParam(field, message string) *validationError {
+	return &validationError{Fields: []fieldError{{Field: field, Message: message}}}
+}
+
+func (e *validationError) add(field, message string) {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return
+		}
+	}
+	e.Fields = append(e.Fields, fieldError{Field: field, Message: message})
+}
+
+// Binds the request into req, a pointer to a struct, and validates it. Fields tagged with params,
+// query and reqHeader are set from the path params, query params and headers, which are left to
+// their default value when absent, and a JSON body is decoded into the fields tagged with json.
+// Untagged struct fields, used for params shared between handlers, are bound the same way.
+// Returns a *validationError listing every invalid param.
+func bind(c *fiber.Ctx, req interface{}) error {
+	verr := &validationError{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			verr.add("body", "must be a JSON object with the documented properties")
+			return verr
+		}
+	}
+	bindParams(c, reflect.ValueOf(req).Elem(), verr)
+
+	err := validate.Struct(req)
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		for _, fe := range errs {
+			verr.add(fe.Field(), fieldMessage(fe))
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if len(verr.Fields) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func bindParams(c *fiber.Ctx, v reflect.Value, verr *validationError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		var name, raw string
+		if name = field.Tag.Get("params"); name != "" {
+			raw = c.Params(name)
+		} else if name = field.Tag.Get("query"); name != "" {
+			raw = c.Query(name)
+		} else if name = field.Tag.Get("reqHeader"); name != "" {
+			raw = c.Get(name)
+		} else {
+			if field.Type.Kind() == reflect.Struct {
+				bindParams(c, v.Field(i), verr)
+			}
+			continue
+		}
+		if raw == "" {
+			continue
+		}
+		if message := setField(v.Field(i), raw); message != "" {
+			verr.add(name, message)
+		}
+	}
+}
+
+// Parses raw into the field. Returns why raw is invalid for the type of the field, if it is.
+// Lists are comma separated.
+func setField(field reflect.Value, raw string) string {
+	if field.Kind() == reflect.Ptr && field.Type().Elem() == timeType {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return "must be an RFC 3339 timestamp"
+		}
+		field.Set(reflect.ValueOf(&t))
+		return ""
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return "must be an integer"
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return "must be a number"
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "must be a boolean"
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		values := make([]string, 0)
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return "is not supported"
+	}
+	return ""
+}
+
+// Returns the message of a failed validation rule
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		plural := "s"
+		if fe.Param() == "1" {
+			plural = ""
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s character%s", bound, fe.Param(), plural)
+		case reflect.Slice:
+			return fmt.Sprintf("must contain %s %s value%s", bound, fe.Param(), plural)
+		default:
+			return fmt.Sprintf("must be %s %s", bound, fe.Param())
+		}
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "language_tag":
+		return "must be a language code such as en or pt-BR, or none"
+	case "video_id":
+		return "must be a YouTube video id"
+	case "api_key":
+		return "must be a YouTube Data API key"
+	case "per_page":
+		return fmt.Sprintf("must be between 1 and %d", configs.GetMaxPerPageLimit())
+	case "batch_ids":
+		return fmt.Sprintf("must contain at most %d ids", configs.GetMaxBatchGetIds())
+	case "webhook_url":
+		return "must be an http or https URL"
+	case "email":
+		return "must be an email address"
+	case "object_id":
+		return "must be a 24 character hexadecimal id"
+	case "cursor":
+		return "must be the next_cursor of a previous page"
+	case "after_published_after":
+		return "must be after published_after"
+	default:
+		return "is invalid"
+	}
+}
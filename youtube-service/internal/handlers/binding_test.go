@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
+)
+
+func TestMain(m *testing.M) {
+	// Limits of the rules are read from the configs, which are left to their defaults
+	os.Setenv("PORT", ":3500")
+	os.Setenv("MONGODB_URI", "mongodb://localhost:27017")
+	os.Setenv("QUERY", "cricket")
+	configs.InitConfig()
+	os.Exit(m.Run())
+}
+
+// Binds a request to the route into req, e.g. a pointer to a request struct of a handler, and
+// returns the params found invalid
+func bindRequest(t *testing.T, route string, target string, body string, req interface{}) []string {
+	t.Helper()
+
+	var bindErr error
+	app := fiber.New()
+	app.All(route, func(c *fiber.Ctx) error {
+		bindErr = bind(c, req)
+		return nil
+	})
+
+	var reader io.Reader
+	method := fiber.MethodGet
+	if body != "" {
+		reader = strings.NewReader(body)
+		method = fiber.MethodPost
+	}
+	httpReq := httptest.NewRequest(method, target, reader)
+	httpReq.Header.Set("Content-Type", "application/json")
+	if _, err := app.Test(httpReq); err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+
+	if bindErr == nil {
+		return nil
+	}
+	var verr *validationError
+	if !errors.As(bindErr, &verr) {
+		t.Fatalf("bind returned %v, want a *validationError", bindErr)
+	}
+	fields := make([]string, 0, len(verr.Fields))
+	for _, field := range verr.Fields {
+		fields = append(fields, field.Field)
+	}
+	return fields
+}
+
+func TestBindSearchVideo(t *testing.T) {
+	cursor := "eyJvIjoibmV3ZXN0IiwiaWQiOiI2MzM1YzZjMmU0YjBhMWYyYzNkNGU1ZjYifQ"
+	tests := []struct {
+		name    string
+		query   string
+		invalid []string
+	}{
+		{"valid", "query=cricket", nil},
+		{"missing query", "", []string{"query"}},
+		{"query of 500 characters", "query=" + strings.Repeat("a", 500), nil},
+		{"query of 501 characters", "query=" + strings.Repeat("a", 501), []string{"query"}},
+		{"first page", "query=cricket&page=1", nil},
+		{"page 0", "query=cricket&page=0", []string{"page"}},
+		{"negative page", "query=cricket&page=-2", []string{"page"}},
+		{"page not a number", "query=cricket&page=two", []string{"page"}},
+		{"per_page 1", "query=cricket&per_page=1", nil},
+		{"per_page at the max limit", "query=cricket&per_page=50", nil},
+		{"per_page 0", "query=cricket&per_page=0", []string{"per_page"}},
+		{"per_page above the max limit", "query=cricket&per_page=51", []string{"per_page"}},
+		{"cursor", "query=cricket&cursor=" + cursor, nil},
+		{"cursor not base64url", "query=cricket&cursor=not%20a%2Bcursor", []string{"cursor"}},
+		{"cursor of 1025 characters", "query=cricket&cursor=" + strings.Repeat("a", 1025), []string{"cursor"}},
+		{"published range", "query=cricket&published_after=2022-01-01T00:00:00Z&published_before=2022-02-01T00:00:00Z", nil},
+		{"published_after alone", "query=cricket&published_after=2022-01-01T00:00:00Z", nil},
+		{"empty published range", "query=cricket&published_after=2022-01-01T00:00:00Z&published_before=2022-01-01T00:00:00Z", []string{"published_before"}},
+		{"reversed published range", "query=cricket&published_after=2022-02-01T00:00:00Z&published_before=2022-01-01T00:00:00Z", []string{"published_before"}},
+		{"published_after not RFC 3339", "query=cricket&published_after=2022-01-01", []string{"published_after"}},
+		{"lang", "query=cricket&lang=en", nil},
+		{"lang with region", "query=cricket&lang=pt-BR", nil},
+		{"lang none", "query=cricket&lang=none", nil},
+		{"lang of one letter", "query=cricket&lang=e", []string{"lang"}},
+		{"lang not a tag", "query=cricket&lang=en%20US", []string{"lang"}},
+		{"language", "query=cricket&language=hi", nil},
+		{"language not a tag", "query=cricket&language=h1", []string{"language"}},
+		{"sort by relevance", "query=cricket&sort=relevance", nil},
+		{"sort by likes", "query=cricket&sort=likes", nil},
+		{"unknown sort", "query=cricket&sort=trending", []string{"sort"}},
+		{"duration", "query=cricket&duration=medium", nil},
+		{"unknown duration", "query=cricket&duration=any", []string{"duration"}},
+		{"live", "query=cricket&live=upcoming", nil},
+		{"unknown live", "query=cricket&live=yes", []string{"live"}},
+		{"min_views 0", "query=cricket&min_views=0", nil},
+		{"min_views", "query=cricket&min_views=1000", nil},
+		{"negative min_views", "query=cricket&min_views=-1", []string{"min_views"}},
+		{"highlight markers of 32 characters", "query=cricket&highlight_pre=" + strings.Repeat("a", 32) + "&highlight_post=" + strings.Repeat("b", 32), nil},
+		{"highlight_pre of 33 characters", "query=cricket&highlight_pre=" + strings.Repeat("a", 33), []string{"highlight_pre"}},
+		{"highlight_post of 33 characters", "query=cricket&highlight_post=" + strings.Repeat("b", 33), []string{"highlight_post"}},
+		{"4 facets", "query=cricket&facets=channel,month,duration,topic", nil},
+		{"5 facets", "query=cricket&facets=channel,month,duration,topic,channel", []string{"facets"}},
+		{"unknown facet", "query=cricket&facets=views", []string{"facets[0]"}},
+		{"several invalid params", "page=0&per_page=0", []string{"page", "per_page", "query"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := searchVideoRequest{Paging: defaultPageQuery()}
+			invalid := bindRequest(t, "/search", "/search?"+test.query, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindGetVideo(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		invalid []string
+	}{
+		{"no params", "", nil},
+		{"sort by views", "sort=views", nil},
+		{"sort by oldest", "sort=oldest", nil},
+		{"sort by relevance", "sort=relevance", []string{"sort"}},
+		{"filters", "duration=short&live=none&min_views=10&language=en", nil},
+		{"invalid filters", "duration=longest&live=past&min_views=-5&language=english-", []string{"duration", "live", "min_views", "language"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := getVideoRequest{Paging: defaultPageQuery()}
+			invalid := bindRequest(t, "/videos", "/videos?"+test.query, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindSuggest(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		invalid []string
+	}{
+		{"prefix", "prefix=cri", nil},
+		{"missing prefix", "", []string{"prefix"}},
+		{"prefix of 100 characters", "prefix=" + strings.Repeat("a", 100), nil},
+		{"prefix of 101 characters", "prefix=" + strings.Repeat("a", 101), []string{"prefix"}},
+		{"limit 1", "prefix=cri&limit=1", nil},
+		{"limit 20", "prefix=cri&limit=20", nil},
+		{"limit 0", "prefix=cri&limit=0", []string{"limit"}},
+		{"limit 21", "prefix=cri&limit=21", []string{"limit"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := suggestRequest{Limit: defaultSuggestLimit}
+			invalid := bindRequest(t, "/suggestions", "/suggestions?"+test.query, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindRelatedVideos(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		invalid []string
+	}{
+		{"default limit", "/videos/dQw4w9WgXcQ/related", nil},
+		{"limit 1", "/videos/dQw4w9WgXcQ/related?limit=1", nil},
+		{"limit 50", "/videos/dQw4w9WgXcQ/related?limit=50", nil},
+		{"limit 0", "/videos/dQw4w9WgXcQ/related?limit=0", []string{"limit"}},
+		{"limit 51", "/videos/dQw4w9WgXcQ/related?limit=51", []string{"limit"}},
+		{"invalid video id", "/videos/nope/related", []string{"uniqueId"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := relatedVideosRequest{Limit: defaultRelatedLimit}
+			invalid := bindRequest(t, "/videos/:uniqueId/related", test.target, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindAddKey(t *testing.T) {
+	key := "AIza" + strings.Repeat("x", 35)
+	tests := []struct {
+		name    string
+		key     string
+		invalid []string
+	}{
+		{"valid", key, nil},
+		{"missing", "", []string{"key"}},
+		{"too short", key[:38], []string{"key"}},
+		{"too long", key + "x", []string{"key"}},
+		{"wrong prefix", "AIzb" + strings.Repeat("x", 35), []string{"key"}},
+		{"invalid character", "AIza" + strings.Repeat("x", 34) + "!", []string{"key"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req addKeyRequest
+			invalid := bindRequest(t, "/keys", "/keys?key="+test.key, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindVideoId(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		invalid []string
+	}{
+		{"valid", "dQw4w9WgXcQ", nil},
+		{"with - and _", "a-b_c-d_e-f", nil},
+		{"10 characters", "dQw4w9WgXc", []string{"uniqueId"}},
+		{"12 characters", "dQw4w9WgXcQQ", []string{"uniqueId"}},
+		{"invalid character", "dQw4w9WgXc.", []string{"uniqueId"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req lookupVideoRequest
+			invalid := bindRequest(t, "/videos/:uniqueId", "/videos/"+test.id, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindBatchGet(t *testing.T) {
+	ids := func(n int) string {
+		quoted := make([]string, n)
+		for i := range quoted {
+			quoted[i] = `"dQw4w9WgXcQ"`
+		}
+		return `{"ids": [` + strings.Join(quoted, ",") + `]}`
+	}
+	tests := []struct {
+		name    string
+		body    string
+		invalid []string
+	}{
+		{"one id", ids(1), nil},
+		{"ids at the max limit", ids(100), nil},
+		{"no ids", `{"ids": []}`, []string{"ids"}},
+		{"missing ids", `{}`, []string{"ids"}},
+		{"ids above the max limit", ids(101), []string{"ids"}},
+		{"invalid id", `{"ids": ["dQw4w9WgXcQ", "nope"]}`, []string{"ids[1]"}},
+		{"not JSON", `ids`, []string{"body"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req batchGetRequest
+			invalid := bindRequest(t, "/videos", "/videos", test.body, &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindObjectId(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		invalid []string
+	}{
+		{"valid", "6335c6c2e4b0a1f2c3d4e5f6", nil},
+		{"upper case", "6335C6C2E4B0A1F2C3D4E5F6", nil},
+		{"23 characters", "6335c6c2e4b0a1f2c3d4e5f", []string{"webhookId"}},
+		{"25 characters", "6335c6c2e4b0a1f2c3d4e5f67", []string{"webhookId"}},
+		{"not hexadecimal", "6335c6c2e4b0a1f2c3d4e5fg", []string{"webhookId"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req webhookRequest
+			invalid := bindRequest(t, "/webhooks/:webhookId", "/webhooks/"+test.id, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindCreateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		invalid []string
+	}{
+		{"https url", `{"url": "https://example.com/hooks"}`, nil},
+		{"http url with port", `{"url": "http://localhost:8080/hooks"}`, nil},
+		{"missing url", `{"topic": "cricket"}`, []string{"url"}},
+		{"url without scheme", `{"url": "example.com/hooks"}`, []string{"url"}},
+		{"ftp url", `{"url": "ftp://example.com/hooks"}`, []string{"url"}},
+		{"url without host", `{"url": "https:///hooks"}`, []string{"url"}},
+		{"url of 2049 characters", `{"url": "https://example.com/` + strings.Repeat("a", 2029) + `"}`, []string{"url"}},
+		{"secret of 16 characters", `{"url": "https://example.com/hooks", "secret": "` + strings.Repeat("s", 16) + `"}`, nil},
+		{"secret of 15 characters", `{"url": "https://example.com/hooks", "secret": "` + strings.Repeat("s", 15) + `"}`, []string{"secret"}},
+		{"secret of 257 characters", `{"url": "https://example.com/hooks", "secret": "` + strings.Repeat("s", 257) + `"}`, []string{"secret"}},
+		{"keyword of 501 characters", `{"url": "https://example.com/hooks", "keyword": "` + strings.Repeat("k", 501) + `"}`, []string{"keyword"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req createWebhookRequest
+			invalid := bindRequest(t, "/webhooks", "/webhooks", test.body, &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindUpdateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		invalid []string
+	}{
+		{"url", `{"url": "https://example.com/hooks"}`, nil},
+		{"empty filters", `{"topic": "", "channel_id": "", "keyword": ""}`, nil},
+		{"url without scheme", `{"url": "example.com/hooks"}`, []string{"url"}},
+		{"short secret", `{"secret": "short"}`, []string{"secret"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req updateWebhookRequest
+			invalid := bindRequest(t, "/webhooks/:webhookId", "/webhooks/6335c6c2e4b0a1f2c3d4e5f6", test.body, &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindListWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		invalid []string
+	}{
+		{"status", "status=dead_lettered", nil},
+		{"unknown status", "status=failed", []string{"status"}},
+		{"limit 100", "limit=100", nil},
+		{"limit 101", "limit=101", []string{"limit"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := listWebhookDeliveriesRequest{Limit: 20}
+			invalid := bindRequest(t, "/webhooks/:webhookId/deliveries", "/webhooks/6335c6c2e4b0a1f2c3d4e5f6/deliveries?"+test.query, "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindCreateAlertRule(t *testing.T) {
+	slack := `"name": "India", "query": "india", "notifier": "slack", "slack_url": "https://hooks.slack.com/services/T/B/x"`
+	email := `"name": "India", "query": "india", "notifier": "email"`
+	tests := []struct {
+		name    string
+		body    string
+		invalid []string
+	}{
+		{"slack", `{` + slack + `}`, nil},
+		{"email", `{` + email + `, "recipients": ["ops@example.com", "team@example.com"]}`, nil},
+		{"recipient with display name", `{` + email + `, "recipients": ["Ops Team <team@example.com>"]}`, []string{"recipients[0]"}},
+		{"missing name and query", `{"notifier": "slack", "slack_url": "https://hooks.slack.com/x"}`, []string{"name", "query"}},
+		{"unknown notifier", `{"name": "India", "query": "india", "notifier": "sms"}`, []string{"notifier"}},
+		{"slack without slack_url", `{"name": "India", "query": "india", "notifier": "slack"}`, []string{"slack_url"}},
+		{"slack_url not http", `{"name": "India", "query": "india", "notifier": "slack", "slack_url": "hooks.slack.com/x"}`, []string{"slack_url"}},
+		{"email without recipients", `{` + email + `}`, []string{"recipients"}},
+		{"email with no recipients", `{` + email + `, "recipients": []}`, []string{"recipients"}},
+		{"invalid recipient", `{` + email + `, "recipients": ["ops@example.com", "ops"]}`, []string{"recipients[1]"}},
+		{"21 recipients", `{` + email + `, "recipients": [` + strings.TrimSuffix(strings.Repeat(`"ops@example.com",`, 21), ",") + `]}`, []string{"recipients"}},
+		{"recipients not required by slack", `{` + slack + `, "recipients": []}`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req createAlertRuleRequest
+			invalid := bindRequest(t, "/alerts", "/alerts", test.body, &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+func TestBindFeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		topic   string
+		invalid []string
+	}{
+		{"topic", "cricket", nil},
+		{"escaped topic", "ind%20vs%20aus", nil},
+		{"topic of 500 characters", strings.Repeat("a", 500), nil},
+		{"topic of 501 characters", strings.Repeat("a", 501), []string{"topic"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var req feedRequest
+			invalid := bindRequest(t, "/feeds/:topic.atom", "/feeds/"+test.topic+".atom", "", &req)
+			if !sameFields(invalid, test.invalid) {
+				t.Errorf("invalid params = %v, want %v", invalid, test.invalid)
+			}
+		})
+	}
+}
+
+// Compares invalid params regardless of their order
+func sameFields(got []string, want []string) bool {
+	if len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	gotSet := make(map[string]bool, len(got))
+	for _, field := range got {
+		gotSet[field] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, field := range want {
+		wantSet[field] = true
+	}
+	return len(got) == len(want) && reflect.DeepEqual(gotSet, wantSet)
+}
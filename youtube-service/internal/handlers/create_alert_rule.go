@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/models-services"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+type createAlertRuleRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Watch phrase, with the syntax of search queries
+	Query      string   `json:"query" validate:"required,max=500"`
+	Topic      string   `json:"topic" validate:"max=500"`
+	ChannelId  string   `json:"channel_id" validate:"max=100"`
+	Notifier   string   `json:"notifier" validate:"required,oneof=slack email"`
+	SlackUrl   string   `json:"slack_url" validate:"required_if=Notifier slack,omitempty,max=2048,webhook_url"`
+	Recipients []string `json:"recipients" validate:"required_if=Notifier email,max=20,dive,email"`
+}
+
+// Checks that email rules have recipients, as required_if accepts an empty list
+func validateCreateAlertRuleRequest(sl validator.StructLevel) {
+	r := sl.Current().Interface().(createAlertRuleRequest)
+	if r.Notifier == entities.NotifierEmail && len(r.Recipients) == 0 {
+		sl.ReportError(r.Recipients, "recipients", "Recipients", "required", "")
+	}
+}
+
+// create_alert_rule handler creates a rule alerting through its notifier on the ingested videos
+// matching its watch phrase
+func Do(c *fiber.Ctx) error {
+	var params createAlertRuleRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+	if _, err := searchquery.Parse(params.Query); err != nil {
+		return invalidRequestResponse(c, invalidParam("query", "must be a valid search query, "+err.Error()))
+	}
+	if params.Notifier == entities.NotifierEmail && configs.GetSmtpHost() == "" {
+		return invalidRequestResponse(c, invalidParam("notifier", "cannot be email while SMTP_HOST is not set"))
+	}
+
+	rule := entities.AlertRule{
+		Name:      params.Name,
+		Query:     params.Query,
+		Topic:     params.Topic,
+		ChannelId: params.ChannelId,
+		Notifier:  params.Notifier,
+	}
+	// Only the destination of the notifier is kept
+	switch params.Notifier {
+	case entities.NotifierSlack:
+		rule.SlackUrl = params.SlackUrl
+	case entities.NotifierEmail:
+		rule.Recipients = params.Recipients
+	}
+
+	rule, err := alerts.CreateRule(rule)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to create alert rule")
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
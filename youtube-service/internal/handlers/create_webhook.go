@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/models-services"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+type createWebhookRequest struct {
+	Url string `json:"url" validate:"required,max=2048,webhook_url"`
+	// Generated when empty
+	Secret    string `json:"secret" validate:"omitempty,min=16,max=256"`
+	Topic     string `json:"topic" validate:"max=500"`
+	ChannelId string `json:"channel_id" validate:"max=100"`
+	Keyword   string `json:"keyword" validate:"max=500"`
+}
+
+// Webhooks are only returned with their secret when they are created
+type createdWebhook struct {
+	entities.Webhook
+	Secret string `json:"secret"`
+}
+
+// create_webhook handler subscribes a URL to the ingested videos matching the topic, channel and
+// keyword of the webhook
+func Do(c *fiber.Ctx) error {
+	var params createWebhookRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+	if params.Keyword != "" {
+		if _, err := searchquery.Parse(params.Keyword); err != nil {
+			return invalidRequestResponse(c, invalidParam("keyword", "must be a valid search query, "+err.Error()))
+		}
+	}
+
+	webhook, err := webhooks.CreateWebhook(entities.Webhook{
+		Url:       params.Url,
+		Secret:    params.Secret,
+		Topic:     params.Topic,
+		ChannelId: params.ChannelId,
+		Keyword:   params.Keyword,
+	})
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to create webhook")
+	}
+	return c.Status(fiber.StatusCreated).JSON(createdWebhook{Webhook: webhook, Secret: webhook.Secret})
+}
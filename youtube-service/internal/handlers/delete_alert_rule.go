@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+// delete_alert_rule handler deletes the alert rule with the given id
+func Do(c *fiber.Ctx) error {
+	var params alertRuleRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	err := alerts.DeleteRule(params.RuleId)
+	if errors.Is(err, alerts.ErrRuleNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "alert rule not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete alert rule")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
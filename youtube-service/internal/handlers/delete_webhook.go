@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+// delete_webhook handler deletes the webhook with the given id along with its deliveries
+func Do(c *fiber.Ctx) error {
+	var params webhookRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	err := webhooks.DeleteWebhook(params.WebhookId)
+	if errors.Is(err, webhooks.ErrWebhookNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete webhook")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
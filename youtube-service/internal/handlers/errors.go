@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Codes of the error body by HTTP status. Other statuses use their snake cased status text.
+var errorCodes = map[int]string{
+	fiber.StatusBadRequest:          "invalid_argument",
+	fiber.StatusUnauthorized:        "unauthenticated",
+	fiber.StatusForbidden:           "permission_denied",
+	fiber.StatusNotFound:            "not_found",
+	fiber.StatusMethodNotAllowed:    "method_not_allowed",
+	fiber.StatusTooManyRequests:     "rate_limited",
+	fiber.StatusInternalServerError: "internal",
+	fiber.StatusServiceUnavailable:  "unavailable",
+}
+
+// Sends the error body shared by all endpoints, with the id of the request so that clients can
+// report it
+func errorResponse(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).JSON(fiber.Map{
+		"error": errorBody(c, status, message),
+	})
+}
+
+// Sends the 400 error body of a request which failed to bind, listing each invalid param in fields
+func invalidRequestResponse(c *fiber.Ctx, err error) error {
+	var verr *validationError
+	if !errors.As(err, &verr) {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	body := errorBody(c, fiber.StatusBadRequest, verr.Error())
+	body["fields"] = verr.Fields
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": body,
+	})
+}
+
+func errorBody(c *fiber.Ctx, status int, message string) fiber.Map {
+	code, ok := errorCodes[status]
+	if !ok {
+		code = strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+	}
+	requestId, _ := c.Locals("requestid").(string)
+	return fiber.Map{
+		"code":       code,
+		"message":    message,
+		"request_id": requestId,
+	}
+}
+
+// ErrorHandler sends errors returned by handlers and middlewares, e.g. for unknown routes, in the
+// shared error body. Details of unexpected errors are only logged.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return errorResponse(c, fiberErr.Code, fiberErr.Message)
+	}
+	log.Errorf("ErrorHandler: Error handling %s %s: %v", c.Method(), c.Path(), err)
+	return errorResponse(c, fiber.StatusInternalServerError, "internal server error")
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/feeds"
+	"github.com/youtube-service/internal/models-services"
+)
+
+// Number of newest videos in feeds
+const feedSize = 50
+
+type feedRequest struct {
+	// Query the videos were fetched for, path escaped
+	Topic string `params:"topic" validate:"required,max=500"`
+}
+
+// Sends the feed of the newest stored videos fetched for the topic, rendered by render. Responds
+// with 304 Not Modified when the ETag or the modification time in the conditional headers of the
+// request are those of the feed.
+func sendFeed(c *fiber.Ctx, contentType string, render func(feeds.Feed) ([]byte, error)) error {
+	var params feedRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+	topic, err := url.PathUnescape(params.Topic)
+	if err != nil {
+		return invalidRequestResponse(c, invalidParam("topic", "is not a valid path segment"))
+	}
+
+	req := entities.PageRequest{Page: 1, PerPage: feedSize, Sort: entities.SortNewest}
+	page, err := models-services.(get_video-search_video).GetVideos(req, entities.VideoFilter{SourceQuery: topic})
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch videos")
+	}
+
+	feed := feeds.Feed{
+		Title:       "YouTube videos: " + topic,
+		Description: "Newest videos fetched from YouTube for " + topic,
+		Url:         c.BaseURL() + c.Path(),
+		HomePageUrl: "https://www.youtube.com/results?search_query=" + url.QueryEscape(topic),
+		Videos:      page.Videos,
+	}
+	body, err := render(feed)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to render feed")
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	updated := feed.Updated()
+	if !updated.IsZero() {
+		c.Set(fiber.HeaderLastModified, updated.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c, etag, updated) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(body)
+}
+
+// Evaluates If-None-Match, or If-Modified-Since when it is absent, as in RFC 7232
+func notModified(c *fiber.Ctx, etag string, updated time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, candidate := range strings.Split(noneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	modifiedSince, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || updated.IsZero() {
+		return false
+	}
+	// Last-Modified has a precision of a second
+	return !updated.Truncate(time.Second).After(modifiedSince)
+}
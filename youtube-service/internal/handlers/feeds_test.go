@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNotModified(t *testing.T) {
+	etag := `"0123456789abcdef"`
+	updated := time.Date(2022, 9, 29, 16, 0, 0, 500*int(time.Millisecond), time.UTC)
+	lastModified := updated.Format(http.TimeFormat)
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		updated     time.Time
+		notModified bool
+	}{
+		{"no conditional headers", nil, updated, false},
+		{"matching etag", map[string]string{fiber.HeaderIfNoneMatch: etag}, updated, true},
+		{"other etag", map[string]string{fiber.HeaderIfNoneMatch: `"other"`}, updated, false},
+		{"etag in a list", map[string]string{fiber.HeaderIfNoneMatch: `"other", ` + etag + `,"third"`}, updated, true},
+		{"weak etag", map[string]string{fiber.HeaderIfNoneMatch: "W/" + etag}, updated, true},
+		{"weak etag in a list", map[string]string{fiber.HeaderIfNoneMatch: `W/"other", W/` + etag}, updated, true},
+		{"any etag", map[string]string{fiber.HeaderIfNoneMatch: "*"}, updated, true},
+		{"Last-Modified, which is truncated to the second", map[string]string{fiber.HeaderIfModifiedSince: lastModified}, updated, true},
+		{"modified after", map[string]string{fiber.HeaderIfModifiedSince: updated.Add(-time.Second).Format(http.TimeFormat)}, updated, false},
+		{"not modified since later", map[string]string{fiber.HeaderIfModifiedSince: updated.Add(time.Hour).Format(http.TimeFormat)}, updated, true},
+		{"invalid date", map[string]string{fiber.HeaderIfModifiedSince: "yesterday"}, updated, false},
+		{"empty feed", map[string]string{fiber.HeaderIfModifiedSince: lastModified}, time.Time{}, false},
+		// If-Modified-Since is ignored when If-None-Match is present
+		{"other etag but not modified since", map[string]string{fiber.HeaderIfNoneMatch: `"other"`, fiber.HeaderIfModifiedSince: lastModified}, updated, false},
+		{"matching etag but modified since", map[string]string{fiber.HeaderIfNoneMatch: etag, fiber.HeaderIfModifiedSince: updated.Add(-time.Hour).Format(http.TimeFormat)}, updated, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got bool
+			app := fiber.New()
+			app.Get("/feed", func(c *fiber.Ctx) error {
+				got = notModified(c, etag, test.updated)
+				return nil
+			})
+			req := httptest.NewRequest(fiber.MethodGet, "/feed", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			if got != test.notModified {
+				t.Errorf("notModified = %v, want %v", got, test.notModified)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/youtube-service/internal/entities"
+)
+
+// Filter query params shared by the video listing and search handlers
+type videoFilterQuery struct {
+	PublishedAfter  *time.Time `query:"published_after"`
+	PublishedBefore *time.Time `query:"published_before"`
+	Duration        string     `query:"duration" validate:"omitempty,oneof=short medium long"`
+	Live            string     `query:"live" validate:"omitempty,oneof=none live upcoming"`
+	MinViews        int64      `query:"min_views" validate:"min=0"`
+	ChannelId       string     `query:"channel_id" validate:"max=64"`
+	Topic           string     `query:"topic" validate:"max=200"`
+	Language        string     `query:"language" validate:"omitempty,language_tag"`
+}
+
+// Checks that the published time range is not empty
+func validateVideoFilterQuery(sl validator.StructLevel) {
+	q := sl.Current().Interface().(videoFilterQuery)
+	if q.PublishedAfter != nil && q.PublishedBefore != nil && !q.PublishedAfter.Before(*q.PublishedBefore) {
+		sl.ReportError(q.PublishedBefore, "published_before", "PublishedBefore", "after_published_after", "")
+	}
+}
+
+func (q videoFilterQuery) filter() entities.VideoFilter {
+	return entities.VideoFilter{
+		PublishedAfter:       q.PublishedAfter,
+		PublishedBefore:      q.PublishedBefore,
+		ChannelId:            q.ChannelId,
+		SourceQuery:          q.Topic,
+		DurationBucket:       q.Duration,
+		MinViewCount:         q.MinViews,
+		DefaultLanguage:      q.Language,
+		LiveBroadcastContent: q.Live,
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type alertRuleRequest struct {
+	RuleId string `params:"ruleId" validate:"object_id"`
+}
+
+// get_alert_rule handler returns the alert rule with the given id
+func Do(c *fiber.Ctx) error {
+	var params alertRuleRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	rule, err := alerts.GetRule(params.RuleId)
+	if errors.Is(err, alerts.ErrRuleNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "alert rule not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch alert rule")
+	}
+	return c.JSON(rule)
+}
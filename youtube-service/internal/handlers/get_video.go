@@ -1,25 +1,35 @@
 package handlers
 
 import (
-	"strconv"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/youtube-service/internal/models-services"
 )
 
-// get_video handler returns all the videos in the database in a paginated manner
+type getVideoRequest struct {
+	Paging pageQuery
+	Filter videoFilterQuery
+	Sort   string `query:"sort" validate:"omitempty,oneof=newest oldest views likes"`
+}
+
+// get_video handler returns all the videos in the database in a paginated manner.
+// Pages can be requested either by number or by the next_cursor of the previous response.
 func Do(c *fiber.Ctx) error {
 
-	page, err := strconv.Atoi(c.Query("page", "1"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "page query param must be an integer",
-		})
+	params := getVideoRequest{Paging: defaultPageQuery()}
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
 	}
+	req := params.Paging.pageRequest(params.Sort)
 
-	videos := models-services.(get_video-search_video).GetVideos(int64(page))
+	page, err := models-services.(get_video-search_video).GetVideos(req, params.Filter.filter())
+	if errors.Is(err, models-services.(get_video-search_video).ErrInvalidCursor) {
+		return invalidRequestResponse(c, invalidParam("cursor", "is invalid"))
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch videos")
+	}
 
-	return c.JSON(fiber.Map{
-		"videos": videos,
-	})
+	return c.JSON(pageResponse(c, req, page))
 }
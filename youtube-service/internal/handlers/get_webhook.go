@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type webhookRequest struct {
+	WebhookId string `params:"webhookId" validate:"object_id"`
+}
+
+// get_webhook handler returns the webhook with the given id
+func Do(c *fiber.Ctx) error {
+	var params webhookRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	webhook, err := webhooks.GetWebhook(params.WebhookId)
+	if errors.Is(err, webhooks.ErrWebhookNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch webhook")
+	}
+	return c.JSON(webhook)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/graphqlapi"
+)
+
+type graphqlRequest struct {
+	Query         string `query:"query" validate:"required"`
+	OperationName string `query:"operationName"`
+	Variables     string `query:"variables"`
+}
+
+// graphql handler executes a GraphQL query sent as query params of a GET request or in the JSON body of a POST request.
+// Mutations can only be sent in POST requests.
+// Requests bearing ADMIN_TOKEN in the Authorization header can manage API keys.
+func Do(c *fiber.Ctx) error {
+	var req graphqlapi.Request
+	if c.Method() == fiber.MethodPost {
+		if err := c.BodyParser(&req); err != nil {
+			return invalidRequestResponse(c, invalidParam("body", "must be a JSON object with the documented properties"))
+		}
+		if req.Query == "" {
+			return invalidRequestResponse(c, invalidParam("query", "is required"))
+		}
+	} else {
+		var params graphqlRequest
+		if err := bind(c, &params); err != nil {
+			return invalidRequestResponse(c, err)
+		}
+		req.Query = params.Query
+		req.OperationName = params.OperationName
+		if params.Variables != "" {
+			if err := json.Unmarshal([]byte(params.Variables), &req.Variables); err != nil {
+				return invalidRequestResponse(c, invalidParam("variables", "must be a JSON object"))
+			}
+		}
+		// Mutations are only executed by POST requests, so that they are not cached or triggered by links
+		if !graphqlapi.IsQuery(req) {
+			c.Set(fiber.HeaderAllow, fiber.MethodPost)
+			return errorResponse(c, fiber.StatusMethodNotAllowed, "mutations must be sent in a POST request")
+		}
+	}
+
+	ctx := graphqlapi.WithAdmin(c.UserContext(), isAdmin(c))
+	return c.JSON(graphqlapi.Execute(ctx, req))
+}
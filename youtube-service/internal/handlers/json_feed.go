@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/feeds"
+)
+
+// json_feed handler returns the newest stored videos of the topic as a JSON Feed 1.1 document
+func Do(c *fiber.Ctx) error {
+	return sendFeed(c, "application/feed+json; charset=utf-8", feeds.JSON)
+}
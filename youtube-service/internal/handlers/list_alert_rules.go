@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+// list_alert_rules handler returns all the alert rules, most recently created first
+func Do(c *fiber.Ctx) error {
+	rules, err := alerts.GetRules()
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch alert rules")
+	}
+	return c.JSON(fiber.Map{
+		"rules": rules,
+	})
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type listWebhookDeliveriesRequest struct {
+	WebhookId string `params:"webhookId" validate:"object_id"`
+	Status    string `query:"status" validate:"omitempty,oneof=pending succeeded dead_lettered"`
+	Limit     int64  `query:"limit" validate:"min=1,max=100"`
+}
+
+// list_webhook_deliveries handler returns the latest deliveries of the webhook with the given id
+// along with their attempts
+func Do(c *fiber.Ctx) error {
+	params := listWebhookDeliveriesRequest{Limit: 20}
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	deliveries, err := webhooks.GetDeliveries(params.WebhookId, params.Status, params.Limit)
+	if errors.Is(err, webhooks.ErrWebhookNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch deliveries")
+	}
+	return c.JSON(fiber.Map{
+		"deliveries": deliveries,
+	})
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+// list_webhooks handler returns all the webhooks, most recently created first
+func Do(c *fiber.Ctx) error {
+	hooks, err := webhooks.GetWebhooks()
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch webhooks")
+	}
+	return c.JSON(fiber.Map{
+		"webhooks": hooks,
+	})
+}
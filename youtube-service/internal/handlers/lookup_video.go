@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type lookupVideoRequest struct {
+	UniqueId string `params:"uniqueId" validate:"video_id"`
+}
+
+// lookup_video handler returns the stored video with the given YouTube id
+func Do(c *fiber.Ctx) error {
+	var params lookupVideoRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	video, err := models-services.(get_video-search_video).GetVideo(params.UniqueId)
+	if errors.Is(err, models-services.(get_video-search_video).ErrVideoNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "video not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch video")
+	}
+
+	return c.JSON(video)
+}
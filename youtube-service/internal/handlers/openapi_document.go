@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/openapi"
+)
+
+// openapi_document handler returns the OpenAPI 3 document of the API
+func Do(c *fiber.Ctx) error {
+	return c.JSON(openapi.GetDocument())
+}
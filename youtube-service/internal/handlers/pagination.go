@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+)
+
+// Query params shared by paginated handlers. Handlers add a sort param restricted to their sort orders.
+type pageQuery struct {
+	Page                 int64   `query:"page" validate:"min=1"`
+	PerPage              int64   `query:"per_page" validate:"per_page"`
+	Cursor               string  `query:"cursor" validate:"omitempty,max=1024,cursor"`
+	RecencyHalfLifeHours float64 `query:"recency_half_life_hours" validate:"min=0"`
+}
+
+// Returns the page query params set to their defaults
+func defaultPageQuery() pageQuery {
+	return pageQuery{
+		Page:                 1,
+		PerPage:              configs.GetPerPageLimit(),
+		RecencyHalfLifeHours: float64(configs.GetRecencyHalfLifeHours()),
+	}
+}
+
+func (q pageQuery) pageRequest(sort string) entities.PageRequest {
+	return entities.PageRequest{
+		Page:                 q.Page,
+		PerPage:              q.PerPage,
+		Cursor:               q.Cursor,
+		Sort:                 sort,
+		RecencyHalfLifeHours: q.RecencyHalfLifeHours,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Builds the response envelope of a paginated handler.
+// page and the prev link are only set for page number based requests.
+func pageResponse(c *fiber.Ctx, req entities.PageRequest, page entities.VideoPage) fiber.Map {
+	links := fiber.Map{
+		"self": c.OriginalURL(),
+	}
+	next := map[string]string{"cursor": page.NextCursor}
+	if page.CorrectedQuery != "" {
+		next["query"] = page.CorrectedQuery
+	}
+	if page.HasMore {
+		links["next"] = pageLink(c, next, "page")
+	}
+
+	res := fiber.Map{
+		"videos":      page.Videos,
+		"per_page":    req.PerPage,
+		"total":       page.Total,
+		"has_more":    page.HasMore,
+		"next_cursor": page.NextCursor,
+		"links":       links,
+	}
+	if page.DidYouMean != nil {
+		res["did_you_mean"] = page.DidYouMean
+	}
+	if page.CorrectedQuery != "" {
+		res["corrected_query"] = page.CorrectedQuery
+	}
+	if req.Cursor == "" {
+		res["page"] = req.Page
+		if req.Page > 1 {
+			links["prev"] = pageLink(c, map[string]string{"page": strconv.FormatInt(req.Page-1, 10)}, "cursor")
+		}
+	}
+	return res
+}
+
+// Returns the current path with the given query params set and the removed ones deleted
+func pageLink(c *fiber.Ctx, set map[string]string, remove ...string) string {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		query = url.Values{}
+	}
+	for key, value := range set {
+		query.Set(key, value)
+	}
+	for _, key := range remove {
+		query.Del(key)
+	}
+	return c.Path() + "?" + query.Encode()
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type redeliverWebhookDeliveryRequest struct {
+	WebhookId  string `params:"webhookId" validate:"object_id"`
+	DeliveryId string `params:"deliveryId" validate:"object_id"`
+}
+
+// redeliver_webhook_delivery handler sends a delivery which succeeded or was dead lettered again
+func Do(c *fiber.Ctx) error {
+	var params redeliverWebhookDeliveryRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	delivery, err := webhooks.Redeliver(params.WebhookId, params.DeliveryId)
+	if errors.Is(err, webhooks.ErrDeliveryNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "delivery not found")
+	}
+	if errors.Is(err, webhooks.ErrDeliveryPending) {
+		return errorResponse(c, fiber.StatusConflict, "delivery is already pending")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to redeliver")
+	}
+	return c.JSON(delivery)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+const defaultRelatedLimit = 10
+
+type relatedVideosRequest struct {
+	UniqueId string `params:"uniqueId" validate:"video_id"`
+	Limit    int64  `query:"limit" validate:"min=1,max=50"`
+}
+
+// related_videos handler returns the stored videos most similar to the given stored video
+func Do(c *fiber.Ctx) error {
+	params := relatedVideosRequest{Limit: defaultRelatedLimit}
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	videos, err := models-services.(get_video-search_video).RelatedVideos(params.UniqueId, params.Limit)
+	if errors.Is(err, models-services.(get_video-search_video).ErrVideoNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "video not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch related videos")
+	}
+
+	return c.JSON(fiber.Map{
+		"videos": videos,
+	})
+}
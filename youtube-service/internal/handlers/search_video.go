@@ -1,29 +1,79 @@
 package handlers
 
 import (
-	"strconv"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
 	"github.com/youtube-service/internal/models-services"
+	"github.com/youtube-service/pkg/searchquery"
 )
 
-// search_video handler returns all the videos matching the search query in the database in a paginated manner
+type searchVideoRequest struct {
+	Paging pageQuery
+	Filter videoFilterQuery
+	Query  string `query:"query" validate:"required,max=500"`
+	// Languages without stemming support are searched without stemming
+	Lang          string   `query:"lang" validate:"omitempty,language_tag"`
+	Sort          string   `query:"sort" validate:"omitempty,oneof=relevance newest oldest views likes"`
+	Debug         bool     `query:"debug"`
+	Facets        []string `query:"facets" validate:"max=4,dive,oneof=channel month duration topic"`
+	HighlightPre  string   `query:"highlight_pre" validate:"max=32"`
+	HighlightPost string   `query:"highlight_post" validate:"max=32"`
+}
+
+// search_video handler returns all the videos matching the search query in the database in a paginated manner.
+// Pages can be requested either by number or by the next_cursor of the previous response.
 func Do(c *fiber.Ctx) error {
-	searchQuery := c.Query("query", "")
-	if searchQuery == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "query param is required",
-		})
+	params := searchVideoRequest{
+		Paging:        defaultPageQuery(),
+		HighlightPre:  configs.GetHighlightPreTag(),
+		HighlightPost: configs.GetHighlightPostTag(),
+	}
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	query, err := searchquery.Parse(params.Query)
+	if err != nil {
+		return invalidRequestResponse(c, invalidParam("query", "must be a valid search query, "+err.Error()))
 	}
+	query.Language = params.Lang
 
-	page, err := strconv.Atoi(c.Query("page", "1"))
+	req := params.Paging.pageRequest(params.Sort)
+	req.Debug = params.Debug
+	req.HighlightPreTag = params.HighlightPre
+	req.HighlightPostTag = params.HighlightPost
+	for _, facet := range params.Facets {
+		if !contains(req.Facets, facet) {
+			req.Facets = append(req.Facets, facet)
+		}
+	}
+	filter := params.Filter.filter()
+
+	page, err := models-services.(get_video-search_video).SearchVideos(query, req, filter)
+	if errors.Is(err, models-services.(get_video-search_video).ErrInvalidCursor) {
+		return invalidRequestResponse(c, invalidParam("cursor", "is invalid"))
+	}
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "page query param must be an integer",
-		})
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to search videos")
 	}
 
-	return c.JSON(fiber.Map{
-		"videos": models-services.(get_video-search_video).SearchVideos(searchQuery, int64(page)),
-	})
+	// Only the first page of searches with results is recorded so that paging does not inflate popularity
+	// When typos were corrected, the corrected query is recorded
+	if req.Cursor == "" && req.Page == 1 && page.Total > 0 {
+		recorded := query
+		if page.CorrectedQuery != "" {
+			recorded, err = searchquery.Parse(page.CorrectedQuery)
+		}
+		if err == nil {
+			models-services.(get_video-search_video).RecordSearchQuery(recorded)
+		}
+	}
+
+	res := pageResponse(c, req, page)
+	if page.Facets != nil {
+		res["facets"] = page.Facets
+	}
+	return c.JSON(res)
 }
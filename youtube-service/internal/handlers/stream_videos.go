@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/models-services"
+	"github.com/youtube-service/internal/pubsub"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+// Interval of the comments keeping idle streams open through proxies, which also detect
+// clients which went away
+const streamKeepAliveInterval = 15 * time.Second
+
+type streamVideosRequest struct {
+	Query     string `query:"query" validate:"max=500"`
+	Topic     string `query:"topic" validate:"max=500"`
+	ChannelId string `query:"channel_id"`
+	// Sent by EventSource when it reconnects, takes precedence over after_id
+	LastEventId string `reqHeader:"Last-Event-ID" validate:"omitempty,object_id"`
+	AfterId     string `query:"after_id" validate:"omitempty,object_id"`
+}
+
+// stream_videos handler pushes the videos inserted in the database as server-sent events whose id
+// is the id of the video, optionally only those matching a search query, topic or channel.
+// Streams resume after the video of the Last-Event-ID header or of the after_id query param.
+func Do(c *fiber.Ctx) error {
+	var params streamVideosRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	var query searchquery.Query
+	if params.Query != "" {
+		var err error
+		if query, err = searchquery.Parse(params.Query); err != nil {
+			return invalidRequestResponse(c, invalidParam("query", "must be a valid search query, "+err.Error()))
+		}
+	}
+	afterId := params.LastEventId
+	if afterId == "" {
+		afterId = params.AfterId
+	}
+	filter := entities.VideoFilter{ChannelId: params.ChannelId, SourceQuery: params.Topic}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Disables response buffering by proxies such as nginx
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Writes fail once the client went away, which ends the stream
+		var mu sync.Mutex
+		write := func(event string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			_, err := w.WriteString(event)
+			if err == nil {
+				err = w.Flush()
+			}
+			if err != nil {
+				cancel()
+			}
+			return err
+		}
+
+		go func() {
+			ticker := time.NewTicker(streamKeepAliveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if write(": keep-alive\n\n") != nil {
+						return
+					}
+				}
+			}
+		}()
+
+		if err := write(": connected\n\n"); err != nil {
+			return
+		}
+		err := models-services.(get_video-search_video).WatchNewVideos(ctx, afterId, filter, func(video entities.Video) error {
+			if len(query.Groups) > 0 && !models-services.(get_video-search_video).MatchesQuery(video, query) {
+				return nil
+			}
+			data, err := json.Marshal(video)
+			if err != nil {
+				return err
+			}
+			return write(fmt.Sprintf("id: %s\nevent: video\ndata: %s\n\n", video.Id, data))
+		})
+		// Clients which fell behind reconnect and catch up from their Last-Event-ID
+		if err != nil && ctx.Err() == nil && !errors.Is(err, pubsub.ErrSlowSubscriber) {
+			log.Errorf("stream_videos: Error streaming videos: %v", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+const defaultSuggestLimit = 5
+
+type suggestRequest struct {
+	Prefix string `query:"prefix" validate:"required,max=100"`
+	Limit  int64  `query:"limit" validate:"min=1,max=20"`
+}
+
+// suggest handler returns video titles and popular search queries completing the prefix typed by the user
+func Do(c *fiber.Ctx) error {
+	params := suggestRequest{Limit: defaultSuggestLimit}
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	titles, queries, err := models-services.(get_video-search_video).Suggest(params.Prefix, params.Limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch suggestions")
+	}
+
+	return c.JSON(fiber.Map{
+		"titles":  titles,
+		"queries": queries,
+	})
+}
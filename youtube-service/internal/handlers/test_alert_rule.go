@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+// test_alert_rule handler sends a test alert through the notifier of the alert rule with the given
+// id, so that its destination can be checked
+func Do(c *fiber.Ctx) error {
+	var params alertRuleRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+
+	err := alerts.TestRule(params.RuleId)
+	if errors.Is(err, alerts.ErrRuleNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "alert rule not found")
+	}
+	if errors.Is(err, alerts.ErrNotifyFailed) {
+		return errorResponse(c, fiber.StatusBadGateway, err.Error())
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to test alert rule")
+	}
+	return c.JSON(fiber.Map{
+		"message": "test alert sent",
+	})
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+)
+
+type trendingRequest struct {
+	Paging pageQuery
+	Filter videoFilterQuery
+	Sort   string `query:"sort" validate:"omitempty,oneof=trending"`
+}
+
+// trending handler returns the videos whose views and likes grew fastest over the trending window
+// in a paginated manner. Accepts the same filters as get_video.
+func Do(c *fiber.Ctx) error {
+	params := trendingRequest{Paging: defaultPageQuery()}
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+	req := params.Paging.pageRequest(params.Sort)
+
+	page, err := models-services.(get_video-search_video).GetTrendingVideos(req, params.Filter.filter())
+	if errors.Is(err, models-services.(get_video-search_video).ErrInvalidCursor) {
+		return invalidRequestResponse(c, invalidParam("cursor", "is invalid"))
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch trending videos")
+	}
+
+	return c.JSON(pageResponse(c, req, page))
+}
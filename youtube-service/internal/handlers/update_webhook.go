@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/models-services"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+// Properties absent from the body are left unchanged, empty filters match every video
+type updateWebhookRequest struct {
+	WebhookId string  `params:"webhookId" validate:"object_id"`
+	Url       *string `json:"url" validate:"omitempty,max=2048,webhook_url"`
+	Secret    *string `json:"secret" validate:"omitempty,min=16,max=256"`
+	Topic     *string `json:"topic" validate:"omitempty,max=500"`
+	ChannelId *string `json:"channel_id" validate:"omitempty,max=100"`
+	Keyword   *string `json:"keyword" validate:"omitempty,max=500"`
+}
+
+// update_webhook handler changes the URL, secret or filters of the webhook with the given id
+func Do(c *fiber.Ctx) error {
+	var params updateWebhookRequest
+	if err := bind(c, &params); err != nil {
+		return invalidRequestResponse(c, err)
+	}
+	if params.Keyword != nil && *params.Keyword != "" {
+		if _, err := searchquery.Parse(*params.Keyword); err != nil {
+			return invalidRequestResponse(c, invalidParam("keyword", "must be a valid search query, "+err.Error()))
+		}
+	}
+
+	webhook, err := webhooks.UpdateWebhook(params.WebhookId, webhooks.WebhookUpdate{
+		Url:       params.Url,
+		Secret:    params.Secret,
+		Topic:     params.Topic,
+		ChannelId: params.ChannelId,
+		Keyword:   params.Keyword,
+	})
+	if errors.Is(err, webhooks.ErrWebhookNotFound) {
+		return errorResponse(c, fiber.StatusNotFound, "webhook not found")
+	}
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to update webhook")
+	}
+	return c.JSON(webhook)
+}
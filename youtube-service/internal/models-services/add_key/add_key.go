@@ -7,19 +7,32 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 )
 
 var collection *mongo.Collection
 
+var ErrKeyNotFound = errors.New("api key not found")
+
 func SetCollection(client *mongo.Client) {
 	collection = client.Database("cmd").Collection("add_key")
 }
 
+// Hides all but the last 4 characters of an API key
+func MaskKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
+
 // Make a single read call to YouTube API to check if the key is valid
 func IsKeyValid(key string) bool {
 	ctx := context.Background()
@@ -38,17 +51,92 @@ func IsKeyValid(key string) bool {
 	return true
 }
 
-// Inserts a new key into the database
-func InsertKey(key string) error {
-	_, err := collection.InsertOne(context.TODO(), bson.M{"key": key, "isExpired": false, "lastUpdated": time.Now()})
+// Inserts a new key into the database and returns it
+func InsertKey(key string) (entities.ApiKey, error) {
+	apiKey := entities.ApiKey{Key: key, IsExpired: false, LastUpdated: time.Now()}
+	result, err := collection.InsertOne(context.TODO(), apiKey)
 	if err != nil {
 		log.Errorf("InsertKey: Error inserting key: %v", err)
-		return err
+		return apiKey, err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		apiKey.Id = id.Hex()
 	}
 	log.Info("InsertKey: Inserted new API key")
+	return apiKey, nil
+}
+
+// Returns all the keys in the database, most recently updated first
+func GetKeys() ([]entities.ApiKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "lastUpdated", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		log.Errorf("GetKeys: Error finding keys: %v", err)
+		return nil, err
+	}
+	keys := make([]entities.ApiKey, 0)
+	if err := cursor.All(ctx, &keys); err != nil {
+		log.Errorf("GetKeys: Error decoding keys: %v", err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Deletes the key with the given id. If it is the key in use, the next valid key is used instead.
+func DeleteKey(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrKeyNotFound
+	}
+	var apiKey entities.ApiKey
+	err = collection.FindOneAndDelete(ctx, bson.M{"_id": objectId}).Decode(&apiKey)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrKeyNotFound
+	}
+	if err != nil {
+		log.Errorf("DeleteKey: Error deleting key: %v", err)
+		return err
+	}
+	if apiKey.Key == configs.GetValidApiKey() {
+		configs.SetValidApiKey("")
+	}
+	log.Info("DeleteKey: Deleted API key")
 	return nil
 }
 
+// Sets whether the key with the given id is expired and returns it. Expiring the key in use makes
+// the next valid key be used instead.
+func SetKeyExpiration(id string, expired bool) (entities.ApiKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var apiKey entities.ApiKey
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apiKey, ErrKeyNotFound
+	}
+	update := bson.M{"$set": bson.M{"isExpired": expired, "lastUpdated": time.Now()}}
+	updateOptions := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = collection.FindOneAndUpdate(ctx, bson.M{"_id": objectId}, update, updateOptions).Decode(&apiKey)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return apiKey, ErrKeyNotFound
+	}
+	if err != nil {
+		log.Errorf("SetKeyExpiration: Error updating key: %v", err)
+		return apiKey, err
+	}
+	if expired && apiKey.Key == configs.GetValidApiKey() {
+		configs.SetValidApiKey("")
+	}
+	return apiKey, nil
+}
+
 func GetValidKey() (string, error) {
 	if configs.GetValidApiKey() != "" {
 		return configs.GetValidApiKey(), nil
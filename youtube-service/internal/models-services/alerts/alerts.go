@@ -0,0 +1,183 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Notifications older than this are deleted by mongo. Videos are only ingested once, so their
+	// alerts are not sent again after that.
+	notificationRetention = 30 * 24 * time.Hour
+	// Time after which a notification claimed by a sender which did not finish it is sent again
+	notificationLease = 5 * time.Minute
+	// Delay before the first retry, doubled on every following retry up to maxRetryDelay
+	retryBaseDelay = 30 * time.Second
+	maxRetryDelay  = 6 * time.Hour
+	// Number of attempts after which a notification which can't be sent is given up
+	maxNotifyAttempts = 8
+)
+
+// Statuses of notifications
+const (
+	notificationPending = "pending"
+	notificationSent    = "sent"
+	notificationFailed  = "failed"
+)
+
+// Alert of a rule on a video. It keeps the rule from alerting on the video again, and stays pending
+// until it is sent.
+type notification struct {
+	Id      primitive.ObjectID `bson:"_id"`
+	RuleId  string             `bson:"ruleId"`
+	VideoId string             `bson:"videoId"`
+	// Video as ingested, sent again by retries
+	Video  entities.Video `bson:"video"`
+	Status string         `bson:"status"`
+	// Number of failed attempts and the error of the last one
+	FailedAttempts int64     `bson:"failedAttempts"`
+	LastError      string    `bson:"lastError,omitempty"`
+	NextAttemptAt  time.Time `bson:"nextAttemptAt"`
+	// Set by RetryPending to find the notifications it claimed
+	Claim     primitive.ObjectID `bson:"claim,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+var (
+	collection              *mongo.Collection
+	notificationsCollection *mongo.Collection
+)
+
+var (
+	ErrRuleNotFound = errors.New("alert rule not found")
+	ErrNotifyFailed = errors.New("failed to send alert")
+)
+
+func SetCollection(client *mongo.Client) {
+	collection = client.Database("cmd").Collection("alert_rules")
+	notificationsCollection = client.Database("cmd").Collection("alert_notifications")
+}
+
+// Creates the unique index de-duplicating the notifications of each rule and video, the indexes of
+// the pending notifications to retry, and the index expiring old notifications
+func CreateIndexes() {
+	models := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "ruleId", Value: 1}, {Key: "videoId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"status": notificationPending}),
+		},
+		{
+			Keys:    bson.D{{Key: "claim", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"status": notificationPending}),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(notificationRetention.Seconds())),
+		},
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := notificationsCollection.Indexes().CreateMany(context.TODO(), models, options)
+	if err != nil {
+		log.Fatalf("CreateIndexes: Error creating alert notification indexes: %v", err)
+	}
+}
+
+// Inserts the rule and returns it with its id
+func CreateRule(rule entities.AlertRule) (entities.AlertRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rule.Id = ""
+	rule.CreatedAt = time.Now()
+	result, err := collection.InsertOne(ctx, rule)
+	if err != nil {
+		log.Errorf("CreateRule: Error inserting alert rule: %v", err)
+		return rule, err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		rule.Id = id.Hex()
+	}
+	log.Infof("CreateRule: Created alert rule %v", rule.Id)
+	return rule, nil
+}
+
+// Returns all the rules, most recently created first
+func GetRules() ([]entities.AlertRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rules, err := findRules(ctx)
+	if err != nil {
+		log.Errorf("GetRules: Error fetching alert rules: %v", err)
+	}
+	return rules, err
+}
+
+func findRules(ctx context.Context) ([]entities.AlertRule, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]entities.AlertRule, 0)
+	err = cursor.All(ctx, &rules)
+	return rules, err
+}
+
+func GetRule(id string) (entities.AlertRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var rule entities.AlertRule
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return rule, ErrRuleNotFound
+	}
+	err = collection.FindOne(ctx, bson.M{"_id": objectId}).Decode(&rule)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return rule, ErrRuleNotFound
+	}
+	if err != nil {
+		log.Errorf("GetRule: Error fetching alert rule: %v", err)
+	}
+	return rule, err
+}
+
+// Deletes the rule with the given id along with its notifications
+func DeleteRule(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRuleNotFound
+	}
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		log.Errorf("DeleteRule: Error deleting alert rule: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrRuleNotFound
+	}
+	if _, err := notificationsCollection.DeleteMany(ctx, bson.M{"ruleId": id}); err != nil {
+		log.Errorf("DeleteRule: Error deleting notifications: %v", err)
+	}
+	log.Infof("DeleteRule: Deleted alert rule %v", id)
+	return nil
+}
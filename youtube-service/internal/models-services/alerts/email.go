@@ -0,0 +1,101 @@
+package alerts
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/youtube-service/internal/configs"
+)
+
+// Port of SMTP servers expecting TLS from the start of the connection rather than STARTTLS
+const implicitTlsPort = 465
+
+var ErrEmailDisabled = errors.New("email alerts are disabled, SMTP_HOST is not set")
+
+// Emails alerts to the recipients of the rule through the SMTP server of the SMTP_* variables
+type emailNotifier struct{}
+
+func (emailNotifier) Notify(alert Alert) error {
+	host := configs.GetSmtpHost()
+	if host == "" {
+		return ErrEmailDisabled
+	}
+	message := emailMessage(configs.GetSmtpFrom(), alert)
+	return sendMail(host, configs.GetSmtpPort(), alert.Rule.Recipients, message)
+}
+
+// Returns the message of the alert with its headers. The subject is encoded so that names and
+// queries of rules cannot inject headers.
+func emailMessage(from string, alert Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(alert.Rule.Recipients, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", alert.summary()))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(alert.text(), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// Sends the message like smtp.SendMail, with timeouts and support of implicit TLS
+func sendMail(host string, port int64, to []string, message []byte) error {
+	address := net.JoinHostPort(host, strconv.FormatInt(port, 10))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if port == implicitTlsPort {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != implicitTlsPort {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if username := configs.GetSmtpUsername(); username != "" {
+		if err := client.Auth(smtp.PlainAuth("", username, configs.GetSmtpPassword(), host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(configs.GetSmtpFrom()); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,236 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/models-services/get_video-search_video"
+	"github.com/youtube-service/pkg/searchquery"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Reports whether the video matches the watch phrase and passes the filters of the rule
+func matches(rule entities.AlertRule, query searchquery.Query, video entities.Video) bool {
+	if rule.Topic != "" && video.SourceQuery != rule.Topic {
+		return false
+	}
+	if rule.ChannelId != "" && video.ChannelId != rule.ChannelId {
+		return false
+	}
+	return get_video-search_video.MatchesQuery(video, query)
+}
+
+// Evaluates the rules against the videos inserted by the ingestion. The matching videos of each
+// rule which were not alerted on yet are recorded as pending notifications, then sent in one alert
+// by the notifier of the rule in the background so that slow notifiers don't hold up the ingestion.
+// Notifications are marked sent once the notifier succeeded, and failed ones are sent again by
+// RetryPending.
+func EvaluateRules(videos []entities.Video) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rules, err := findRules(ctx)
+	if err != nil {
+		log.Errorf("EvaluateRules: Error fetching alert rules: %v", err)
+		return
+	}
+
+	for _, rule := range rules {
+		query, err := searchquery.Parse(rule.Query)
+		if err != nil {
+			log.Errorf("EvaluateRules: Error parsing query of alert rule %v: %v", rule.Id, err)
+			continue
+		}
+		pending := make([]notification, 0)
+		for _, video := range videos {
+			if !matches(rule, query, video) {
+				continue
+			}
+			n, first, err := recordNotification(ctx, rule, video)
+			if err != nil {
+				log.Errorf("EvaluateRules: Error recording notification: %v", err)
+				continue
+			}
+			if first {
+				pending = append(pending, n)
+			}
+		}
+		if len(pending) > 0 {
+			go notify(rule, pending)
+		}
+	}
+}
+
+// Records the pending notification of the rule on the video, leased so that RetryPending doesn't
+// send it concurrently. Returns false when the rule already alerted on the video.
+func recordNotification(ctx context.Context, rule entities.AlertRule, video entities.Video) (notification, bool, error) {
+	now := time.Now()
+	n := notification{
+		Id:            primitive.NewObjectID(),
+		RuleId:        rule.Id,
+		VideoId:       video.UniqueId,
+		Video:         video,
+		Status:        notificationPending,
+		NextAttemptAt: now.Add(notificationLease),
+		CreatedAt:     now,
+	}
+	_, err := notificationsCollection.InsertOne(ctx, n)
+	if mongo.IsDuplicateKeyError(err) {
+		return n, false, nil
+	}
+	return n, err == nil, err
+}
+
+// Sends the pending notifications of the rule in one alert and records the result
+func notify(rule entities.AlertRule, pending []notification) {
+	alert := Alert{Rule: rule, Videos: make([]entities.Video, 0, len(pending))}
+	for _, n := range pending {
+		alert.Videos = append(alert.Videos, n.Video)
+	}
+
+	var err error
+	notifier, ok := notifiers[rule.Notifier]
+	if !ok {
+		err = fmt.Errorf("unknown notifier %v", rule.Notifier)
+	} else {
+		err = notifier.Notify(alert)
+	}
+	if err != nil {
+		log.Errorf("notify: Error sending alert of rule %v: %v", rule.Id, err)
+	} else {
+		log.Infof("notify: Sent alert of rule %v on %v videos", rule.Id, len(alert.Videos))
+	}
+	recordAttempt(pending, err)
+}
+
+// Marks the notifications sent, or schedules their retry with exponential backoff unless they
+// failed maxNotifyAttempts times
+func recordAttempt(pending []notification, notifyErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	for _, n := range pending {
+		set := bson.M{}
+		failedAttempts := n.FailedAttempts + 1
+		switch {
+		case notifyErr == nil:
+			set["status"] = notificationSent
+		case failedAttempts >= maxNotifyAttempts:
+			set["status"] = notificationFailed
+			set["failedAttempts"] = failedAttempts
+			set["lastError"] = notifyErr.Error()
+			log.Errorf("recordAttempt: Gave up notification %v of rule %v after %v attempts: %v", n.Id.Hex(), n.RuleId, failedAttempts, notifyErr)
+		default:
+			set["failedAttempts"] = failedAttempts
+			set["lastError"] = notifyErr.Error()
+			set["nextAttemptAt"] = now.Add(retryDelay(failedAttempts))
+		}
+		update := bson.M{"$set": set, "$unset": bson.M{"claim": ""}}
+		if _, err := notificationsCollection.UpdateByID(ctx, n.Id, update); err != nil {
+			log.Errorf("recordAttempt: Error recording attempt of notification %v: %v", n.Id.Hex(), err)
+		}
+	}
+}
+
+// Returns the delay before retrying a notification which failed the given number of times
+func retryDelay(failedAttempts int64) time.Duration {
+	if failedAttempts > 16 {
+		return maxRetryDelay
+	}
+	delay := retryBaseDelay << (failedAttempts - 1)
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// Sends the pending notifications which are due again, in one alert per rule. This also sends the
+// notifications of alerts which were interrupted, e.g. by a restart, once their lease expired.
+func RetryPending() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Claims the due notifications so that other instances don't send them concurrently
+	now := time.Now()
+	claim := primitive.NewObjectID()
+	filter := bson.M{"status": notificationPending, "nextAttemptAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"claim": claim, "nextAttemptAt": now.Add(notificationLease)}}
+	result, err := notificationsCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Errorf("RetryPending: Error claiming notifications: %v", err)
+		return
+	}
+	if result.ModifiedCount == 0 {
+		return
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := notificationsCollection.Find(ctx, bson.M{"claim": claim, "status": notificationPending}, findOptions)
+	if err != nil {
+		log.Errorf("RetryPending: Error fetching notifications: %v", err)
+		return
+	}
+	claimed := make([]notification, 0)
+	if err := cursor.All(ctx, &claimed); err != nil {
+		log.Errorf("RetryPending: Error decoding notifications: %v", err)
+		return
+	}
+
+	ruleIds := make([]string, 0)
+	byRule := make(map[string][]notification)
+	for _, n := range claimed {
+		if _, ok := byRule[n.RuleId]; !ok {
+			ruleIds = append(ruleIds, n.RuleId)
+		}
+		byRule[n.RuleId] = append(byRule[n.RuleId], n)
+	}
+	for _, ruleId := range ruleIds {
+		rule, err := GetRule(ruleId)
+		if err != nil {
+			// Deleted rules have no notifications left, the others are sent once their lease expired
+			continue
+		}
+		notify(rule, byRule[ruleId])
+	}
+}
+
+// Sends a test alert of the rule with the given id, with the newest stored video matching it if
+// any. Errors of the notifier are wrapped in ErrNotifyFailed. Test alerts are not recorded.
+func TestRule(id string) error {
+	rule, err := GetRule(id)
+	if err != nil {
+		return err
+	}
+	query, err := searchquery.Parse(rule.Query)
+	if err != nil {
+		return err
+	}
+	notifier, ok := notifiers[rule.Notifier]
+	if !ok {
+		return fmt.Errorf("unknown notifier %v", rule.Notifier)
+	}
+
+	req := entities.PageRequest{Page: 1, PerPage: 1, Sort: entities.SortNewest}
+	videoFilter := entities.VideoFilter{SourceQuery: rule.Topic, ChannelId: rule.ChannelId}
+	page, err := get_video-search_video.SearchVideos(query, req, videoFilter)
+	if err != nil {
+		return err
+	}
+	alert := Alert{Rule: rule, Videos: make([]entities.Video, 0), Test: true}
+	// Results of a corrected query don't match the rule
+	if page.CorrectedQuery == "" {
+		alert.Videos = page.Videos
+	}
+	if err := notifier.Notify(alert); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotifyFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package alerts
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Videos matching a rule, sent together by the notifier of the rule
+type Alert struct {
+	Rule   entities.AlertRule
+	Videos []entities.Video
+	// Sent by the test endpoint, with the newest stored video matching the rule if any
+	Test bool
+}
+
+// Notifier sends alerts to the destination configured in their rule
+type Notifier interface {
+	Notify(alert Alert) error
+}
+
+// Notifier plugins by the notifier name of the rules. New notifiers are added here and to the
+// validation of the create_alert_rule handler.
+var notifiers = map[string]Notifier{
+	entities.NotifierSlack: slackNotifier{},
+	entities.NotifierEmail: emailNotifier{},
+}
+
+// Returns the plain text summary line of the alert, e.g. used as the email subject
+func (a Alert) summary() string {
+	prefix := ""
+	if a.Test {
+		prefix = "[Test] "
+	}
+	switch len(a.Videos) {
+	case 0:
+		return fmt.Sprintf("%s%s: no video matching %s yet", prefix, a.Rule.Name, a.Rule.Query)
+	case 1:
+		return fmt.Sprintf("%s%s: new video matching %s", prefix, a.Rule.Name, a.Rule.Query)
+	}
+	return fmt.Sprintf("%s%s: %d new videos matching %s", prefix, a.Rule.Name, len(a.Videos), a.Rule.Query)
+}
+
+func videoUrl(video entities.Video) string {
+	return "https://www.youtube.com/watch?v=" + video.UniqueId
+}
+
+// Returns the plain text body of the alert, one video per paragraph
+func (a Alert) text() string {
+	var b strings.Builder
+	if a.Test && len(a.Videos) == 0 {
+		b.WriteString("No stored video matches this rule yet, alerts will be sent like this one.\n")
+	}
+	for _, video := range a.Videos {
+		fmt.Fprintf(&b, "%s\n%s - %s\n%s\n\n", video.Title, video.ChannelTitle, video.PublishedAt.Format(time.RFC1123), videoUrl(video))
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Escapes the characters which slack interprets as markup in message text
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Posts alerts to a Slack compatible incoming webhook, e.g. of Slack, Mattermost or Rocket.Chat
+type slackNotifier struct{}
+
+func (slackNotifier) Notify(alert Alert) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*%s*\n", slackEscaper.Replace(alert.summary()))
+	if alert.Test && len(alert.Videos) == 0 {
+		text.WriteString("No stored video matches this rule yet, alerts will be sent like this one.\n")
+	}
+	for _, video := range alert.Videos {
+		fmt.Fprintf(&text, "• <%s|%s> by %s\n", videoUrl(video), slackEscaper.Replace(video.Title), slackEscaper.Replace(video.ChannelTitle))
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text.String()})
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Post(alert.Rule.SlackUrl, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("slack webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package get_video-search_video
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is the position of the last video of a page in the requested sort order.
+// _id breaks ties between videos with the same sort value.
+type pageCursor struct {
+	Sort        string     `json:"o"`
+	PublishedAt *time.Time `json:"p,omitempty"`
+	Value       *float64   `json:"v,omitempty"`
+	// Reference time of the recency decay, so that scores do not drift between pages
+	Now *time.Time `json:"n,omitempty"`
+	Id  string     `json:"id"`
+}
+
+// Encodes a cursor into an opaque token that can be passed back by clients
+func encodeCursor(c pageCursor) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decodes a token created by encodeCursor for the given sort order.
+// Returns ErrInvalidCursor if the token is malformed or was created for another sort order.
+func decodeCursor(token string, sort string) (pageCursor, primitive.ObjectID, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, primitive.NilObjectID, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, primitive.NilObjectID, ErrInvalidCursor
+	}
+	if c.Sort != sort {
+		return c, primitive.NilObjectID, ErrInvalidCursor
+	}
+	if c.PublishedAt == nil && c.Value == nil {
+		return c, primitive.NilObjectID, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(c.Id)
+	if err != nil {
+		return c, primitive.NilObjectID, ErrInvalidCursor
+	}
+	return c, id, nil
+}
+
+// Returns the cursor pointing at the given video
+func cursorAt(order sortOrder, video entities.Video, now *time.Time) string {
+	c := pageCursor{Sort: order.name, Id: video.Id, Now: now}
+	switch order.field {
+	case "publishedAt":
+		c.PublishedAt = &video.PublishedAt
+	case "viewCount":
+		v := float64(video.ViewCount)
+		c.Value = &v
+	case "likeCount":
+		v := float64(video.LikeCount)
+		c.Value = &v
+	case "trending.score":
+		v := 0.0
+		if video.Trending != nil {
+			v = video.Trending.Score
+		}
+		c.Value = &v
+	default:
+		c.Value = &video.Score
+	}
+	return encodeCursor(c)
+}
+
+// Returns a filter matching videos after the cursor position in the given sort order
+func afterCursor(c pageCursor, id primitive.ObjectID, order sortOrder) bson.M {
+	var value interface{}
+	if c.PublishedAt != nil {
+		value = *c.PublishedAt
+	} else {
+		value = *c.Value
+	}
+
+	op := "$lt"
+	if order.direction > 0 {
+		op = "$gt"
+	}
+	return bson.M{
+		"$or": bson.A{
+			bson.M{order.field: bson.M{op: value}},
+			bson.M{order.field: value, "_id": bson.M{op: id}},
+		},
+	}
+}
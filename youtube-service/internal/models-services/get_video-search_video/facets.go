@@ -0,0 +1,79 @@
+package get_video-search_video
+
+import (
+	"context"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Max number of values returned per facet, the ones with the most videos first
+const maxFacetValues = 20
+
+// Returns the $facet sub-pipeline counting videos per value of the facet.
+// Videos without a value, e.g. stored before durations were fetched, are not counted.
+func facetPipeline(facet string) bson.A {
+	var group bson.M
+	sort := bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}
+	switch facet {
+	case entities.FacetChannel:
+		group = bson.M{"_id": "$channelId", "label": bson.M{"$first": "$channelTitle"}}
+	case entities.FacetMonth:
+		group = bson.M{"_id": bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$publishedAt"}}}
+		// Months are listed latest first rather than by count
+		sort = bson.D{{Key: "_id", Value: -1}}
+	case entities.FacetDuration:
+		group = bson.M{"_id": "$durationBucket"}
+	case entities.FacetTopic:
+		group = bson.M{"_id": "$sourceQuery"}
+	}
+	group["count"] = bson.M{"$sum": 1}
+
+	return bson.A{
+		bson.M{"$group": group},
+		bson.M{"$match": bson.M{"_id": bson.M{"$nin": bson.A{nil, ""}}}},
+		bson.M{"$sort": sort},
+		bson.M{"$limit": maxFacetValues},
+	}
+}
+
+// Counts the videos matched by the pipeline per value of each facet in a single $facet stage
+func searchFacets(ctx context.Context, matchPipeline mongo.Pipeline, facets []string) (map[string][]entities.FacetCount, error) {
+	facetStage := bson.M{}
+	for _, facet := range facets {
+		facetStage[facet] = facetPipeline(facet)
+	}
+	pipeline := append(append(mongo.Pipeline{}, matchPipeline...), bson.D{{Key: "$facet", Value: facetStage}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []map[string][]struct {
+		Value string `bson:"_id"`
+		Label string `bson:"label"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string][]entities.FacetCount, len(facets))
+	for _, facet := range facets {
+		counts[facet] = make([]entities.FacetCount, 0)
+		if len(results) == 0 {
+			continue
+		}
+		for _, value := range results[0][facet] {
+			counts[facet] = append(counts[facet], entities.FacetCount{
+				Value: value.Value,
+				Label: value.Label,
+				Count: value.Count,
+			})
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,82 @@
+package get_video-search_video
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Translates a video filter into a mongo filter
+func filterQuery(videoFilter entities.VideoFilter) bson.M {
+	filter := bson.M{}
+
+	publishedAt := bson.M{}
+	if videoFilter.PublishedAfter != nil {
+		publishedAt["$gte"] = *videoFilter.PublishedAfter
+	}
+	if videoFilter.PublishedBefore != nil {
+		publishedAt["$lt"] = *videoFilter.PublishedBefore
+	}
+	if len(publishedAt) > 0 {
+		filter["publishedAt"] = publishedAt
+	}
+
+	if videoFilter.ChannelId != "" {
+		filter["channelId"] = videoFilter.ChannelId
+	}
+	if videoFilter.SourceQuery != "" {
+		filter["sourceQuery"] = videoFilter.SourceQuery
+	}
+	if videoFilter.DurationBucket != "" {
+		filter["durationBucket"] = videoFilter.DurationBucket
+	}
+	if videoFilter.MinViewCount > 0 {
+		filter["viewCount"] = bson.M{"$gte": videoFilter.MinViewCount}
+	}
+	if videoFilter.DefaultLanguage != "" {
+		filter["defaultLanguage"] = videoFilter.DefaultLanguage
+	}
+	if videoFilter.LiveBroadcastContent != "" {
+		filter["liveBroadcastContent"] = videoFilter.LiveBroadcastContent
+	}
+	return filter
+}
+
+// Creates compound indexes supporting each filter combined with the latest first sort,
+// and indexes supporting the views and likes sorts
+func CreateFilterIndexes() {
+	fields := []string{"channelId", "sourceQuery", "durationBucket", "defaultLanguage", "liveBroadcastContent"}
+
+	models := make([]mongo.IndexModel, 0, len(fields)+2)
+	for _, field := range fields {
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: field, Value: 1},
+				{Key: "publishedAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		})
+	}
+	// Indexes for sorting by statistics
+	for _, field := range []string{"viewCount", "likeCount"} {
+		models = append(models, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: field, Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		})
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := collection.Indexes().CreateMany(context.TODO(), models, options)
+	if err != nil {
+		log.Fatalf("CreateFilterIndexes: Error creating indexes: %v", err)
+	}
+}
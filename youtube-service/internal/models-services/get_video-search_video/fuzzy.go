@@ -0,0 +1,401 @@
+package get_video-search_video
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/pkg/searchquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Dictionary of the terms of stored titles and descriptions with the number of videos containing them
+var termsCollection *mongo.Collection
+
+const (
+	minDictionaryTermLength = 3
+	maxDidYouMean           = 3
+	// Max number of dictionary terms whose edit distance to a word is computed, the ones sharing the
+	// most bigrams with the word
+	maxCorrectionCandidates = 200
+)
+
+type dictionaryTerm struct {
+	Term   string `bson:"term"`
+	Length int    `bson:"length"`
+	Count  int64  `bson:"count"`
+	// Distinct bigrams of the term, used to find the terms close to a word
+	Grams []string `bson:"grams"`
+}
+
+// Returns the distinct bigrams of the term padded with ^ and $, e.g. ^c, ca, at, t$ for cat, so that
+// the first and last characters count as much as the others
+func termGrams(term string) []string {
+	runes := append(append([]rune{'^'}, []rune(term)...), '$')
+	seen := make(map[string]bool, len(runes))
+	grams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		gram := string(runes[i : i+2])
+		if !seen[gram] {
+			seen[gram] = true
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
+
+// Returns the min number of bigrams a term within maxDistance edits of the word shares with it. An
+// edit changes at most 2 bigrams of the word, and a transposition 3.
+func minSharedGrams(word string, maxDistance int) int {
+	shared := len(termGrams(word)) - 3*maxDistance
+	if shared < 1 {
+		return 1
+	}
+	return shared
+}
+
+// Returns the distinct terms of a video which are added to the dictionary
+func videoTerms(video entities.Video) []string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0)
+	for _, term := range strings.Fields(normalizeForSuggest(video.Title + " " + video.Description)) {
+		if utf8.RuneCountInString(term) < minDictionaryTermLength || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Creates the indexes of the term dictionary
+func CreateDictionaryIndexes() {
+	models := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "term", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "length", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "grams", Value: 1}, {Key: "length", Value: 1}},
+		},
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := termsCollection.Indexes().CreateMany(context.TODO(), models, options)
+	if err != nil {
+		log.Fatalf("CreateDictionaryIndexes: Error creating indexes: %v", err)
+	}
+}
+
+// Increments the count of the given terms in the dictionary, adding the new ones
+func incrementTerms(ctx context.Context, counts map[string]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(counts))
+	for term, count := range counts {
+		update := bson.M{
+			"$inc":         bson.M{"count": count},
+			"$setOnInsert": bson.M{"length": utf8.RuneCountInString(term), "grams": termGrams(term)},
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"term": term}).SetUpdate(update))
+	}
+	_, err := termsCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Adds the terms of newly inserted videos to the dictionary.
+// Errors are only logged as the dictionary is only used for suggestions.
+func addTermsToDictionary(videos []entities.Video) {
+	counts := make(map[string]int64)
+	for _, video := range videos {
+		for _, term := range videoTerms(video) {
+			counts[term]++
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := incrementTerms(ctx, counts); err != nil {
+		log.Errorf("addTermsToDictionary: Error adding terms: %v", err)
+	}
+}
+
+// Builds the term dictionary from the stored videos if it is empty
+func MigrateDictionary() {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	count, err := termsCollection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		log.Fatalf("MigrateDictionary: Error counting terms: %v", err)
+	}
+	if count > 0 {
+		return
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"title": 1, "description": 1}))
+	if err != nil {
+		log.Fatalf("MigrateDictionary: Error finding videos: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[string]int64)
+	for cursor.Next(ctx) {
+		var video entities.Video
+		if err := cursor.Decode(&video); err != nil {
+			log.Errorf("MigrateDictionary: Error decoding video: %v", err)
+			continue
+		}
+		for _, term := range videoTerms(video) {
+			counts[term]++
+		}
+	}
+
+	if err := incrementTerms(ctx, counts); err != nil {
+		log.Fatalf("MigrateDictionary: Error adding terms: %v", err)
+	}
+	if len(counts) > 0 {
+		log.Infof("MigrateDictionary: Added %v terms to the dictionary", len(counts))
+	}
+}
+
+// Sets the bigrams of the dictionary terms added before they were maintained on insert
+func MigrateDictionaryGrams() {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	cursor, err := termsCollection.Find(ctx, bson.M{"grams": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"term": 1}))
+	if err != nil {
+		log.Fatalf("MigrateDictionaryGrams: Error finding terms: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	models := make([]mongo.WriteModel, 0)
+	for cursor.Next(ctx) {
+		var term struct {
+			Id   interface{} `bson:"_id"`
+			Term string      `bson:"term"`
+		}
+		if err := cursor.Decode(&term); err != nil {
+			log.Errorf("MigrateDictionaryGrams: Error decoding term: %v", err)
+			continue
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": term.Id}).
+			SetUpdate(bson.M{"$set": bson.M{"grams": termGrams(term.Term)}}))
+	}
+	if len(models) == 0 {
+		return
+	}
+
+	res, err := termsCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		log.Fatalf("MigrateDictionaryGrams: Error setting bigrams: %v", err)
+	}
+	log.Infof("MigrateDictionaryGrams: Set bigrams of %v terms", res.ModifiedCount)
+}
+
+// Max edit distance of a correction, so that short words are not corrected into unrelated ones
+func maxEditDistance(word string) int {
+	if utf8.RuneCountInString(word) <= 4 {
+		return 1
+	}
+	return 2
+}
+
+// Returns the edit distance between a and b, counting a transposition of adjacent characters as one edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// Returns the dictionary terms closest to the word, the most frequent first among equally close ones.
+// Returns nil if the word is in the dictionary.
+// Only the maxCorrectionCandidates terms of a close length sharing the most bigrams with the word are
+// compared to it, so that the number of edit distances computed is bounded. Finding them still reads
+// every term of a close length sharing a bigram with the word.
+func corrections(ctx context.Context, word string) ([]string, error) {
+	known, err := termsCollection.CountDocuments(ctx, bson.M{"term": word}, options.Count().SetLimit(1))
+	if err != nil || known > 0 {
+		return nil, err
+	}
+
+	length := utf8.RuneCountInString(word)
+	maxDistance := maxEditDistance(word)
+	grams := termGrams(word)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"grams":  bson.M{"$in": grams},
+			"length": bson.M{"$gte": length - maxDistance, "$lte": length + maxDistance},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"term":   1,
+			"count":  1,
+			"shared": bson.M{"$size": bson.M{"$setIntersection": bson.A{"$grams", grams}}},
+		}}},
+		{{Key: "$match", Value: bson.M{"shared": bson.M{"$gte": minSharedGrams(word, maxDistance)}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "shared", Value: -1}, {Key: "count", Value: -1}}}},
+		{{Key: "$limit", Value: maxCorrectionCandidates}},
+	}
+	cursor, err := termsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type candidate struct {
+		term     string
+		distance int
+		count    int64
+	}
+	candidates := make([]candidate, 0)
+	for cursor.Next(ctx) {
+		var term dictionaryTerm
+		if err := cursor.Decode(&term); err != nil {
+			return nil, err
+		}
+		if distance := editDistance(word, term.Term); distance <= maxDistance {
+			candidates = append(candidates, candidate{term.Term, distance, term.Count})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].count > candidates[j].count
+	})
+
+	terms := make([]string, 0, maxDidYouMean)
+	for _, c := range candidates {
+		if len(terms) == maxDidYouMean {
+			break
+		}
+		terms = append(terms, c.term)
+	}
+	return terms, nil
+}
+
+// Returns up to maxDidYouMean queries in which the words of the query that are not in the
+// dictionary are replaced by their closest terms. The first query uses the closest term for
+// every word, the next ones use the next closest terms. Only correctable terms are corrected.
+func didYouMean(query searchquery.Query) ([]searchquery.Query, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	wordCorrections := make(map[string][]string)
+	for _, term := range query.PositiveTerms() {
+		if !correctable(term) {
+			continue
+		}
+		for _, word := range strings.Fields(normalizeForSuggest(term.Value)) {
+			if _, ok := wordCorrections[word]; ok || utf8.RuneCountInString(word) < minDictionaryTermLength {
+				continue
+			}
+			terms, err := corrections(ctx, word)
+			if err != nil {
+				return nil, err
+			}
+			wordCorrections[word] = terms
+		}
+	}
+
+	suggestions := make([]searchquery.Query, 0, maxDidYouMean)
+	seen := make(map[string]bool)
+	for i := 0; i < maxDidYouMean; i++ {
+		corrected := correctQuery(query, wordCorrections, i)
+		s := corrected.String()
+		if s == query.String() || seen[s] {
+			continue
+		}
+		seen[s] = true
+		suggestions = append(suggestions, corrected)
+	}
+	return suggestions, nil
+}
+
+// Returns true if the words of the term can be replaced by dictionary terms. Negated terms and phrases
+// are kept as typed, and so are field terms whose values, e.g. channel ids, are not in the dictionary
+// of title and description words.
+func correctable(term searchquery.Term) bool {
+	return !term.Negated && !term.Phrase && term.Field == ""
+}
+
+// Returns a copy of the query with each word replaced by its n-th correction, or by its closest
+// one if it has fewer corrections
+func correctQuery(query searchquery.Query, wordCorrections map[string][]string, n int) searchquery.Query {
+	corrected := searchquery.Query{
+		Groups:   make([][]searchquery.Term, 0, len(query.Groups)),
+		Language: query.Language,
+	}
+	for _, group := range query.Groups {
+		terms := make([]searchquery.Term, 0, len(group))
+		for _, term := range group {
+			if correctable(term) {
+				words := strings.Fields(normalizeForSuggest(term.Value))
+				changed := false
+				for i, word := range words {
+					if c := wordCorrections[word]; len(c) > n {
+						words[i] = c[n]
+						changed = true
+					} else if len(c) > 0 {
+						words[i] = c[0]
+						changed = true
+					}
+				}
+				if changed {
+					term.Value = strings.Join(words, " ")
+				}
+			}
+			terms = append(terms, term)
+		}
+		corrected.Groups = append(corrected.Groups, terms)
+	}
+	return corrected
+}
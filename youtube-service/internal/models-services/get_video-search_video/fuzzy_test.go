@@ -0,0 +1,91 @@
+package get_video-search_video
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"cricket", "cricket", 0},
+		{"crikcet", "cricket", 1},
+		{"criket", "cricket", 1},
+		{"crickett", "cricket", 1},
+		{"crocket", "cricket", 1},
+		{"krikett", "cricket", 3},
+		{"café", "cafe", 1},
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b); got != test.distance {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.distance)
+		}
+	}
+}
+
+func TestTermGrams(t *testing.T) {
+	want := []string{"^b", "bo", "oo", "ok", "k$"}
+	if got := termGrams("book"); !reflect.DeepEqual(got, want) {
+		t.Errorf("termGrams(book) = %v, want %v", got, want)
+	}
+}
+
+// Terms within the max edit distance of a word must not be filtered out by the bigram prefilter
+func TestMinSharedGramsKeepsCloseTerms(t *testing.T) {
+	tests := []struct {
+		word, term string
+	}{
+		{"criket", "cricket"},
+		{"crikcet", "cricket"},
+		{"xricket", "cricket"},
+		{"cricktt", "cricket"},
+		{"rcicket", "cricket"},
+		{"highlihgts", "highlights"},
+		{"hgihlights", "highlights"},
+		{"aus", "ausi"},
+		{"sua", "usa"},
+		{"ipl", "ipk"},
+		{"banana", "bnanaa"},
+	}
+	for _, test := range tests {
+		maxDistance := maxEditDistance(test.word)
+		if editDistance(test.word, test.term) > maxDistance {
+			t.Fatalf("%q is not within %d edits of %q", test.term, maxDistance, test.word)
+		}
+		shared := 0
+		termGramSet := make(map[string]bool)
+		for _, gram := range termGrams(test.term) {
+			termGramSet[gram] = true
+		}
+		for _, gram := range termGrams(test.word) {
+			if termGramSet[gram] {
+				shared++
+			}
+		}
+		if min := minSharedGrams(test.word, maxDistance); shared < min {
+			t.Errorf("%q shares %d bigrams with %q, fewer than the %d required", test.term, shared, test.word, min)
+		}
+	}
+}
+
+func TestCorrectQueryKeepsFieldTermsAndPhrases(t *testing.T) {
+	query, err := searchquery.Parse(`cricekt channel:UCxyz "ind vs aus" -highlihgts title:live`)
+	if err != nil {
+		t.Fatalf("Parse returned error %v", err)
+	}
+	wordCorrections := map[string][]string{
+		"cricekt":    {"cricket"},
+		"ucxyz":      {"ucxya"},
+		"ind":        {"india"},
+		"highlihgts": {"highlights"},
+		"live":       {"love"},
+	}
+	want := `cricket channel:UCxyz "ind vs aus" -highlihgts title:live`
+	if got := correctQuery(query, wordCorrections, 0).String(); got != want {
+		t.Errorf("correctQuery = %q, want %q", got, want)
+	}
+}
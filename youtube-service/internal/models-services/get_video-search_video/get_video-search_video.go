@@ -7,9 +7,14 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/youtube-service/internal/entities"
 	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/pubsub"
+	"github.com/youtube-service/models-services/outbox"
+	"github.com/youtube-service/pkg/language"
+	"github.com/youtube-service/pkg/searchquery"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/api/option"
@@ -26,60 +31,189 @@ const (
 
 func SetCollection(client *mongo.Client) {
 	collection = client.Database("cmd").Collection("get_video-search_video")
+	queriesCollection = client.Database("cmd").Collection("search_queries")
+	termsCollection = client.Database("cmd").Collection("search_terms")
+	statsCollection = client.Database("cmd").Collection("video_stats")
+	ingestionRunsCollection = client.Database("cmd").Collection("ingestion_runs")
 }
 
-// Creates a compound text index of title and description so that they can be queried together
+// Creates a compound text index of title, description and tags so that they can be queried together.
+// Each document is indexed in the language stored in textLanguage.
+// The index is re-created if the configured weights differ from those of the existing one.
 func CreateTitleAndDescriptionIndex() {
+	weights := textIndexWeights()
 	model := mongo.IndexModel{
 		Keys: bson.D{
 			{Key: "title", Value: "text"},
 			{Key: "description", Value: "text"},
+			{Key: "tags", Value: "text"},
 		},
-		Options: options.Index().SetWeights(bson.D{
-			{Key: "title", Value: 1},
-			{Key: "description", Value: 1},
-		}).SetCollation(&options.Collation{
-			Locale: "simple"}),
+		Options: options.Index().SetName(textIndexName).SetWeights(weights).
+			SetDefaultLanguage(language.Default).SetLanguageOverride(textLanguageField).
+			SetCollation(&options.Collation{
+				Locale: "simple"}),
+	}
+
+	exists, err := dropTextIndexIfChanged(weights)
+	if err != nil {
+		log.Fatalf("CreateTitleAndDescriptionIndex: Error checking existing text index: %v", err)
+	}
+	if exists {
+		return
 	}
 
 	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
 
-	_, err := collection.Indexes().CreateOne(context.TODO(), model, options)
+	_, err = collection.Indexes().CreateOne(context.TODO(), model, options)
 	if err != nil {
 		log.Fatalf("SetCollection: Error creating index: %v", err)
 	}
 }
 
+// Creates a descending index on publishedAt so that the latest videos can be listed first.
+// _id breaks ties between videos published at the same time.
+func CreatePublishedAtIndex() {
+	model := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "publishedAt", Value: -1},
+			{Key: "_id", Value: -1},
+		},
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), model, options)
+	if err != nil {
+		log.Fatalf("CreatePublishedAtIndex: Error creating index: %v", err)
+	}
+}
+
+// Converts publishedAt of documents stored before it was saved as a date from
+// an RFC 3339 string to a BSON date. Documents already migrated are not touched.
+func MigratePublishedAtToDate() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"publishedAt": bson.M{"$type": "string"}}
+	update := mongo.Pipeline{
+		bson.D{{Key: "$set", Value: bson.M{
+			"publishedAt": bson.M{"$toDate": "$publishedAt"},
+		}}},
+	}
+
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Fatalf("MigratePublishedAtToDate: Error migrating publishedAt: %v", err)
+	}
+	if res.ModifiedCount > 0 {
+		log.Infof("MigratePublishedAtToDate: Migrated publishedAt of %v documents", res.ModifiedCount)
+	}
+}
+
 // Inserts multiple entries into the youtube-video-info collection
 // Do nothing if the entry already exists
-func bulkInsert(videos []types.Video) error {
+// Returns the videos which were inserted
+// When the event outbox is enabled, the events of the inserted videos are stored in the same
+// transaction, so that no video is inserted without its event. The ingestion fails if either write
+// fails, and the videos are inserted again by the next fetch.
+func bulkInsert(videos []types.Video) ([]entities.Video, error) {
+	ctx := context.Background()
+	var inserted []entities.Video
+	var err error
+	if outbox.Enabled() {
+		inserted, err = upsertVideosWithEvents(ctx, videos)
+	} else {
+		inserted, err = upsertVideos(ctx, videos)
+	}
+	if err != nil {
+		return nil, err
+	}
+	addTermsToDictionary(inserted)
+	return inserted, nil
+}
+
+// Upserts the videos and enqueues the events of the inserted ones in a single transaction
+func upsertVideosWithEvents(ctx context.Context, videos []entities.Video) ([]entities.Video, error) {
+	session, err := collection.Database().Client().StartSession()
+	if err != nil {
+		log.Errorf("BulkInsert: Error starting session: %v", err)
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		inserted, err := upsertVideos(sc, videos)
+		if err != nil {
+			return nil, err
+		}
+		if err := outbox.Enqueue(sc, inserted); err != nil {
+			return nil, err
+		}
+		return inserted, nil
+	})
+	if err != nil {
+		log.Errorf("BulkInsert: Error inserting videos and their events: %v", err)
+		return nil, err
+	}
+	return result.([]entities.Video), nil
+}
+
+// Upserts the videos by their YouTube id and returns the ones which were inserted
+func upsertVideos(ctx context.Context, videos []entities.Video) ([]entities.Video, error) {
 	models := make([]mongo.WriteModel, 0)
 	for _, video := range videos {
 		videoBson := bson.M{
-			"title":       video.Title,
-			"description": video.Description,
-			"publishedAt": video.PublishedAt,
+			"title":           video.Title,
+			"description":     video.Description,
+			"publishedAt":     video.PublishedAt,
+			"channelId":       video.ChannelId,
+			"channelTitle":    video.ChannelTitle,
+			"sourceQuery":     video.SourceQuery,
+			"durationSeconds": video.DurationSeconds,
+			"durationBucket":  video.DurationBucket,
+			"defaultLanguage": video.DefaultLanguage,
+			"tags":            video.Tags,
+			"titlePrefixes":   suggestPrefixes(video.Title),
+			"textLanguage":    video.TextLanguage,
 		}
+		// Statistics and live status change over time so they are updated on every fetch
 		query := bson.M{
 			"$setOnInsert": videoBson,
-			"$set":         bson.M{},
+			"$set": bson.M{
+				"viewCount":            video.ViewCount,
+				"likeCount":            video.LikeCount,
+				"liveBroadcastContent": video.LiveBroadcastContent,
+			},
 		}
 
 		models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).SetUpdate(query).SetFilter(bson.M{"uniqueId": video.UniqueId}))
 	}
 	opts := options.BulkWrite().SetOrdered(false)
-	res, err := collection.BulkWrite(context.Background(), models, opts)
+	res, err := collection.BulkWrite(ctx, models, opts)
 	if err != nil {
 		log.Errorf("BulkInsert: Error inserting many: %v", err)
-		return err
+		return nil, err
 	}
 	log.Infof("BulkInsert: Inserted %v documents into collection", res.UpsertedCount)
-	return nil
+
+	// Upserted ids are keyed by the index of the video in the bulk write
+	inserted := make([]entities.Video, 0, len(res.UpsertedIDs))
+	for i, video := range videos {
+		id, ok := res.UpsertedIDs[int64(i)]
+		if !ok {
+			continue
+		}
+		if oid, ok := id.(primitive.ObjectID); ok {
+			video.Id = oid.Hex()
+		}
+		inserted = append(inserted, video)
+	}
+	return inserted, nil
 }
 
 // Fetches videos from youtube api and inserts them into the database.
-// If the etag is same, do nothing.
-func FetchNewVideosAndUpdateDb() error {
+// If the etag is same, do nothing. Sets the counts and status of the run.
+func fetchNewVideosAndUpdateDb(run *entities.IngestionRun) error {
 	ctx := context.Background()
 
 	// avoids unnecessary multiple calls to the database
@@ -118,6 +252,7 @@ func FetchNewVideosAndUpdateDb() error {
 	if err != nil {
 		if err.Error() == "googleapi: got HTTP response code 304 with body: " {
 			log.Info("FetchNewVideosAndUpdateDb: Etag has not changed. Skipping update.")
+			run.Status = entities.IngestionSkipped
 			return nil
 		}
 		if strings.Contains(err.Error(), "quotaExceeded") {
@@ -139,128 +274,421 @@ func FetchNewVideosAndUpdateDb() error {
 		return err
 	}
 
-	videos := make([]types.Video, 0)
+	videos := make([]entities.Video, 0)
 	for _, item := range response.Items {
-		video := types.Video{
-			UniqueId:    item.Id.VideoId,
-			Title:       item.Snippet.Title,
-			Description: item.Snippet.Description,
-			PublishedAt: item.Snippet.PublishedAt,
+		publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+		if err != nil {
+			log.Errorf("FetchNewVideosAndUpdateDb: Error parsing publishedAt of video %v: %v", item.Id.VideoId, err)
+			continue
+		}
+		video := entities.Video{
+			UniqueId:             item.Id.VideoId,
+			Title:                item.Snippet.Title,
+			Description:          item.Snippet.Description,
+			PublishedAt:          publishedAt,
+			ChannelId:            item.Snippet.ChannelId,
+			ChannelTitle:         item.Snippet.ChannelTitle,
+			SourceQuery:          configs.GetQuery(),
+			LiveBroadcastContent: item.Snippet.LiveBroadcastContent,
 		}
 		videos = append(videos, video)
 	}
 
+	// Search results do not contain duration, statistics, tags or language, so they are fetched separately.
+	// Videos are still stored without them if the call fails.
+	err = addVideoDetails(youtubeService, videos)
+	if err != nil {
+		log.Errorf("FetchNewVideosAndUpdateDb: Error fetching video details: %v", err)
+	}
+	for i := range videos {
+		videos[i].TextLanguage = textLanguage(videos[i])
+	}
+
 	log.Infof("FetchNewVideosAndUpdateDb: Fetched %v videos. Updating the database.", len(videos))
-	err = bulkInsert(videos)
+	run.Fetched = int64(len(videos))
+	inserted, err := bulkInsert(videos)
 	if err != nil {
 		log.Errorf("FetchNewVideosAndUpdateDb: Error inserting into db: %v", err)
 		return err
 	}
+	run.Inserted = int64(len(inserted))
+	pubsub.Publish(inserted)
 
 	config.SetEtag(response.Etag)
 	return nil
 }
 
-// Get videos from database in paginated format
-func GetVideos(currPage int64) []types.Video {
+// Sets duration, statistics, tags and language of the videos using a single videos.list call
+func addVideoDetails(youtubeService *youtube.Service, videos []entities.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(videos))
+	for _, video := range videos {
+		ids = append(ids, video.UniqueId)
+	}
+
+	response, err := youtubeService.Videos.List([]string{"snippet", "contentDetails", "statistics"}).
+		Id(ids...).
+		MaxResults(int64(len(ids))).
+		Do()
+	if err != nil {
+		return err
+	}
+
+	details := make(map[string]*youtube.Video, len(response.Items))
+	for _, item := range response.Items {
+		details[item.Id] = item
+	}
+
+	for i := range videos {
+		item, ok := details[videos[i].UniqueId]
+		if !ok {
+			continue
+		}
+		if item.ContentDetails != nil {
+			seconds, err := utils.ParseISO8601Duration(item.ContentDetails.Duration)
+			if err != nil {
+				log.Errorf("addVideoDetails: Error parsing duration of video %v: %v", item.Id, err)
+			} else {
+				videos[i].DurationSeconds = seconds
+				videos[i].DurationBucket = durationBucket(seconds)
+			}
+		}
+		if item.Statistics != nil {
+			videos[i].ViewCount = int64(item.Statistics.ViewCount)
+			videos[i].LikeCount = int64(item.Statistics.LikeCount)
+		}
+		if item.Snippet != nil {
+			videos[i].Tags = item.Snippet.Tags
+			videos[i].DefaultLanguage = item.Snippet.DefaultLanguage
+			if videos[i].DefaultLanguage == "" {
+				videos[i].DefaultLanguage = item.Snippet.DefaultAudioLanguage
+			}
+		}
+	}
+	return nil
+}
+
+// Buckets durations the same way as the videoDuration filter of the YouTube search API
+func durationBucket(seconds int64) string {
+	switch {
+	case seconds < 4*60:
+		return entities.DurationShort
+	case seconds <= 20*60:
+		return entities.DurationMedium
+	default:
+		return entities.DurationLong
+	}
+}
+
+// Get videos from database in paginated format, latest published first unless another sort order is requested.
+// Only videos matching the filter are returned.
+// If a cursor is requested, videos after the cursor are returned and the page number is ignored.
+func GetVideos(req entities.PageRequest, videoFilter entities.VideoFilter) (entities.VideoPage, error) {
+	order, err := getSortOrder(req.Sort, entities.SortNewest)
+	if err != nil || order.name == entities.SortRelevance || order.name == entities.SortTrending {
+		return entities.VideoPage{}, ErrInvalidSort
+	}
+	return findVideos("GetVideos", req, order, filterQuery(videoFilter))
+}
+
+// Returns a page of the videos matching the filter in the given sort order.
+// caller prefixes the logged errors.
+func findVideos(caller string, req entities.PageRequest, order sortOrder, filter bson.M) (entities.VideoPage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	var page entities.VideoPage
+
+	total, err := countVideos(ctx, filter)
+	if err != nil {
+		log.Errorf("%s: Error counting videos: %v", caller, err)
+		return page, err
+	}
+	page.Total = total
+
 	// Searches for videos in a paginated manner
+	// One extra video is fetched to know whether there is a next page
 	findOptions := options.Find()
-	findOptions.SetSkip((currPage - 1) * config.GetPerPageLimit())
-	findOptions.SetLimit(config.GetPerPageLimit())
+	findOptions.SetSort(order.sort())
+	if req.Cursor != "" {
+		c, id, err := decodeCursor(req.Cursor, order.name)
+		if err != nil {
+			return page, err
+		}
+		filter = bson.M{"$and": bson.A{filter, afterCursor(c, id, order)}}
+	} else {
+		findOptions.SetSkip((req.Page - 1) * req.PerPage)
+	}
+	findOptions.SetLimit(req.PerPage + 1)
 
-	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	dbCursor, err := collection.Find(ctx, filter, findOptions)
 	if err != nil {
-		log.Errorf("GetVideos: Error fetching videos: %v", err)
-		return nil
+		log.Errorf("%s: Error fetching videos: %v", caller, err)
+		return page, err
 	}
-	defer cursor.Close(ctx)
+	defer dbCursor.Close(ctx)
 
-	videos := make([]types.Video, 0)
-	for cursor.Next(ctx) {
-		var video types.Video
-		err := cursor.Decode(&video)
+	videos := make([]entities.Video, 0)
+	for dbCursor.Next(ctx) {
+		var video entities.Video
+		err := dbCursor.Decode(&video)
 		if err != nil {
-			log.Errorf("GetVideos: Error decoding video: %v", err)
+			log.Errorf("%s: Error decoding video: %v", caller, err)
 			continue
 		}
 		videos = append(videos, video)
 	}
-	return videos
+
+	if int64(len(videos)) > req.PerPage {
+		videos = videos[:req.PerPage]
+		last := videos[len(videos)-1]
+		page.HasMore = true
+		page.NextCursor = cursorAt(order, last, nil)
+	}
+	page.Videos = videos
+	return page, nil
 }
 
-// Searches videos in the database using the given query
-// Sorts videos according to score and shows videos with a score greater than 1
-// Returns the videos in paginated format
-func SearchVideos(query string, currPage int64) []types.Video {
+// Counts videos matching the filter. Uses the collection metadata when there is no filter
+// so that listing all videos does not scan the whole collection.
+func countVideos(ctx context.Context, filter bson.M) (int64, error) {
+	if len(filter) == 0 {
+		return collection.EstimatedDocumentCount(ctx)
+	}
+	return collection.CountDocuments(ctx, filter)
+}
+
+// Searches videos like searchVideos. When the first page has fewer results than the configured
+// threshold, e.g. because of a typo, the words of the query are corrected using the term dictionary.
+// The corrections are returned as did you mean suggestions, and if the closest one has more results
+// than the query, its results are returned instead.
+func SearchVideos(query searchquery.Query, req entities.PageRequest, videoFilter entities.VideoFilter) (entities.VideoPage, error) {
+	page, err := searchVideos(query, req, videoFilter)
+	threshold := configs.GetFuzzyFallbackThreshold()
+	if err != nil || threshold == 0 || req.Cursor != "" || req.Page != 1 || page.Total >= threshold {
+		return page, err
+	}
+
+	suggestions, err := didYouMean(query)
+	if err != nil {
+		// Suggestions are best effort, the results of the query are still returned
+		log.Errorf("SearchVideos: Error finding corrections: %v", err)
+		return page, nil
+	}
+	if len(suggestions) == 0 {
+		return page, nil
+	}
+
+	page.DidYouMean = make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		page.DidYouMean = append(page.DidYouMean, suggestion.String())
+	}
+
+	corrected, err := searchVideos(suggestions[0], req, videoFilter)
+	if err != nil {
+		log.Errorf("SearchVideos: Error searching corrected query: %v", err)
+		return page, nil
+	}
+	if corrected.Total <= page.Total {
+		return page, nil
+	}
+	log.Infof("SearchVideos: Returning results of corrected query %v", suggestions[0])
+	corrected.DidYouMean = page.DidYouMean
+	corrected.CorrectedQuery = suggestions[0].String()
+	return corrected, nil
+}
+
+// Searches videos matching the filter in the database using the given query
+// Sorts videos according to score unless another sort order is requested and shows videos with a score
+// greater than the configured minimum. In debug mode each video has the breakdown of its score.
+// Queries without terms that can use the text index, e.g. only field scoped terms, are not scored.
+// Counts of all the matched videos per value of the requested facets are returned along with the page.
+// When a recency half life is requested, the score of relevance sorting decays with the age of the video.
+// Returns the videos in paginated format. If a cursor is requested, results after
+// the cursor are returned and the page number is ignored.
+func searchVideos(query searchquery.Query, req entities.PageRequest, videoFilter entities.VideoFilter) (entities.VideoPage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	log.Infof("SearchVideos: Searching for %v", query)
 
-	perPageLimit := config.GetPerPageLimit()
-	// search only in title and description
-	filter := bson.M{
-		"$text": bson.M{
-			"$search": query,
-		},
+	var page entities.VideoPage
+	order, err := getSortOrder(req.Sort, entities.SortRelevance)
+	if err != nil || order.name == entities.SortTrending {
+		return page, ErrInvalidSort
+	}
+
+	// search only in title, description and tags
+	filter := filterQuery(videoFilter)
+	search, conditions := searchQueryFilter(query)
+	if search != "" {
+		text := bson.M{
+			"$search": search,
+		}
+		if query.Language != "" {
+			text["$language"] = language.TextSearchLanguage(query.Language)
+		}
+		filter["$text"] = text
+	}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
 	}
 
 	firstMatchStage := bson.D{
 		{Key: "$match", Value: filter},
 	}
+	// textScore keeps the text score when score is blended with recency
 	addFieldsStage := bson.D{
 		{Key: "$addFields", Value: bson.M{
 			"score": bson.M{
 				"$meta": "textScore",
 			},
+			"textScore": bson.M{
+				"$meta": "textScore",
+			},
 		}},
 	}
 	secondMatchStage := bson.D{
 		{Key: "$match", Value: bson.M{
 			"score": bson.M{
-				"$gte": 1,
+				"$gte": configs.GetMinSearchScore(),
 			},
 		}},
 	}
 	sortStage := bson.D{
-		{Key: "$sort", Value: bson.M{
-			"score": -1,
-		}},
-	}
-	setSkip := bson.D{
-		{Key: "$skip", Value: (currPage - 1) * perPageLimit},
+		{Key: "$sort", Value: order.sort()},
 	}
+	// One extra video is fetched to know whether there is a next page
 	setLimit := bson.D{
-		{Key: "$limit", Value: perPageLimit},
+		{Key: "$limit", Value: req.PerPage + 1},
+	}
+	countStage := bson.D{
+		{Key: "$count", Value: "total"},
 	}
 
-	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{firstMatchStage, addFieldsStage, secondMatchStage, sortStage, setSkip, setLimit})
+	matchPipeline := mongo.Pipeline{firstMatchStage, addFieldsStage, secondMatchStage}
+	if search == "" {
+		unscoredStage := bson.D{
+			{Key: "$addFields", Value: bson.M{
+				"score":     bson.M{"$literal": 0},
+				"textScore": bson.M{"$literal": 0},
+			}},
+		}
+		matchPipeline = mongo.Pipeline{firstMatchStage, unscoredStage}
+	}
 
+	total, err := countSearchResults(ctx, append(matchPipeline, countStage))
+	if err != nil {
+		log.Errorf("SearchVideos: Error counting videos: %v", err)
+		return page, err
+	}
+	page.Total = total
+
+	if len(req.Facets) > 0 {
+		page.Facets, err = searchFacets(ctx, matchPipeline, req.Facets)
+		if err != nil {
+			log.Errorf("SearchVideos: Error counting facets: %v", err)
+			return page, err
+		}
+	}
+
+	pipeline := append(mongo.Pipeline{}, matchPipeline...)
+
+	// The reference time of the decay is carried by the cursor so that scores do not change between pages
+	var c pageCursor
+	var id primitive.ObjectID
+	if req.Cursor != "" {
+		c, id, err = decodeCursor(req.Cursor, order.name)
+		if err != nil {
+			return page, err
+		}
+	}
+	var now *time.Time
+	if order.name == entities.SortRelevance && req.RecencyHalfLifeHours > 0 {
+		if c.Now == nil {
+			t := time.Now().UTC()
+			c.Now = &t
+		}
+		now = c.Now
+		pipeline = append(pipeline, recencyDecayStage(*now, req.RecencyHalfLifeHours))
+	}
+
+	if req.Cursor != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: afterCursor(c, id, order)}}, sortStage)
+	} else {
+		setSkip := bson.D{
+			{Key: "$skip", Value: (req.Page - 1) * req.PerPage},
+		}
+		pipeline = append(pipeline, sortStage, setSkip)
+	}
+	pipeline = append(pipeline, setLimit)
+
+	dbCursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		log.Errorf("SearchVideos: Error searching videos: %v", err)
-		return nil
+		return page, err
 	}
-	defer cursor.Close(ctx)
+	defer dbCursor.Close(ctx)
 
-	var results []bson.M
-	err = cursor.All(ctx, &results)
+	videos := make([]entities.Video, 0)
+	err = dbCursor.All(ctx, &videos)
 	if err != nil {
 		log.Errorf("SearchVideos: Error decoding videos: %v", err)
-		return nil
+		return page, err
 	}
 
-	videos := make([]types.Video, 0)
-	for _, result := range results {
-		video := types.Video{
-			UniqueId:    result["uniqueId"].(string),
-			Title:       result["title"].(string),
-			Description: result["description"].(string),
-			PublishedAt: result["publishedAt"].(string),
+	if int64(len(videos)) > req.PerPage {
+		videos = videos[:req.PerPage]
+		last := videos[len(videos)-1]
+		page.HasMore = true
+		page.NextCursor = cursorAt(order, last, now)
+	}
+	highlightVideos(query, videos, req.HighlightPreTag, req.HighlightPostTag)
+	if req.Debug {
+		for i := range videos {
+			videos[i].ScoreBreakdown = scoreBreakdown(query, videos[i], req.RecencyHalfLifeHours, now)
 		}
-		videos = append(videos, video)
 	}
-	return videos
+	page.Videos = videos
+	return page, nil
+}
+
+// Returns a stage multiplying the text score by 0.5^(age / half life) so that fresh videos rank higher.
+// Videos published after now, e.g. upcoming live streams, are not boosted.
+func recencyDecayStage(now time.Time, halfLifeHours float64) bson.D {
+	halfLifeMs := halfLifeHours * float64(time.Hour/time.Millisecond)
+	ageMs := bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{now, "$publishedAt"}}}}
+	return bson.D{
+		{Key: "$addFields", Value: bson.M{
+			"score": bson.M{
+				"$multiply": bson.A{
+					"$score",
+					bson.M{"$pow": bson.A{0.5, bson.M{"$divide": bson.A{ageMs, halfLifeMs}}}},
+				},
+			},
+		}},
+	}
+}
+
+// Runs a pipeline ending in a $count stage and returns the count
+func countSearchResults(ctx context.Context, pipeline mongo.Pipeline) (int64, error) {
+	dbCursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer dbCursor.Close(ctx)
+
+	var results []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := dbCursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Total, nil
 }
@@ -0,0 +1,46 @@
+package get_video-search_video
+
+import (
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/pkg/highlight"
+	"github.com/youtube-service/pkg/language"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+// Sets the highlighted title and description snippet of each search result. Terms of the
+// query are stemmed like the text index does in the language of the video, so that stemmed
+// matches are highlighted too. Field scoped terms are only highlighted in their field.
+func highlightVideos(query searchquery.Query, videos []entities.Video, pre string, post string) {
+	titleValues := make([]string, 0)
+	descriptionValues := make([]string, 0)
+	for _, term := range query.PositiveTerms() {
+		switch term.Field {
+		case "":
+			titleValues = append(titleValues, term.Value)
+			descriptionValues = append(descriptionValues, term.Value)
+		case searchquery.FieldTitle:
+			titleValues = append(titleValues, term.Value)
+		case searchquery.FieldDescription:
+			descriptionValues = append(descriptionValues, term.Value)
+		}
+	}
+
+	// Terms are stemmed once per language of the results
+	titleTerms := make(map[string]highlight.Terms)
+	descriptionTerms := make(map[string]highlight.Terms)
+	for i := range videos {
+		stemmer := language.StemmerLanguage(videos[i].TextLanguage)
+		if _, ok := titleTerms[stemmer]; !ok {
+			titleTerms[stemmer] = highlight.NewTerms(stemmer, titleValues...)
+			descriptionTerms[stemmer] = highlight.NewTerms(stemmer, descriptionValues...)
+		}
+
+		title, _ := highlight.Highlight(videos[i].Title, titleTerms[stemmer], pre, post)
+		description, _ := highlight.Snippet(videos[i].Description, descriptionTerms[stemmer], int(configs.GetSnippetLength()), pre, post)
+		videos[i].Highlights = &entities.Highlights{
+			Title:       title,
+			Description: description,
+		}
+	}
+}
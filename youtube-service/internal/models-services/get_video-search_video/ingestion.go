@@ -0,0 +1,79 @@
+package get_video-search_video
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Runs of the job fetching the latest videos
+var ingestionRunsCollection *mongo.Collection
+
+// Runs older than this are deleted by mongo
+const ingestionRunRetention = 30 * 24 * time.Hour
+
+// Creates the index listing the latest ingestion runs, which also expires old runs
+func CreateIngestionRunIndexes() {
+	model := mongo.IndexModel{
+		Keys:    bson.D{{Key: "startedAt", Value: -1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(ingestionRunRetention.Seconds())),
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := ingestionRunsCollection.Indexes().CreateOne(context.TODO(), model, options)
+	if err != nil {
+		log.Fatalf("CreateIngestionRunIndexes: Error creating index: %v", err)
+	}
+}
+
+// Fetches the latest videos from youtube and updates the database like fetchNewVideosAndUpdateDb,
+// recording the run so that the status of the ingestion can be checked
+func FetchNewVideosAndUpdateDb() error {
+	run := entities.IngestionRun{
+		Query:     configs.GetQuery(),
+		StartedAt: time.Now().UTC(),
+		Status:    entities.IngestionSucceeded,
+	}
+	err := fetchNewVideosAndUpdateDb(&run)
+	if err != nil {
+		run.Status = entities.IngestionFailed
+		run.Error = err.Error()
+	}
+	run.FinishedAt = time.Now().UTC()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, insertErr := ingestionRunsCollection.InsertOne(ctx, run); insertErr != nil {
+		log.Errorf("FetchNewVideosAndUpdateDb: Error recording ingestion run: %v", insertErr)
+	}
+	return err
+}
+
+// Returns the latest runs of the job fetching videos, most recent first
+func GetIngestionRuns(limit int64) ([]entities.IngestionRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "startedAt", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := ingestionRunsCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		log.Errorf("GetIngestionRuns: Error fetching ingestion runs: %v", err)
+		return nil, err
+	}
+	runs := make([]entities.IngestionRun, 0)
+	if err := cursor.All(ctx, &runs); err != nil {
+		log.Errorf("GetIngestionRuns: Error decoding ingestion runs: %v", err)
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,71 @@
+package get_video-search_video
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/pkg/language"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Returns the language in which a video is indexed: its YouTube default language if set,
+// else the language detected from the script of its title and description
+func textLanguage(video entities.Video) string {
+	tag := video.DefaultLanguage
+	if tag == "" {
+		tag = language.Detect(video.Title + " " + video.Description)
+	}
+	if textLanguage := language.TextSearchLanguage(tag); textLanguage != "" {
+		return textLanguage
+	}
+	return language.Default
+}
+
+// Sets the text language of videos stored before it was set on insert
+func MigrateTextLanguage() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{textLanguageField: bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"title": 1, "description": 1, "defaultLanguage": 1}))
+	if err != nil {
+		log.Fatalf("MigrateTextLanguage: Error finding videos: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	models := make([]mongo.WriteModel, 0)
+	for cursor.Next(ctx) {
+		var video struct {
+			Id              interface{} `bson:"_id"`
+			Title           string      `bson:"title"`
+			Description     string      `bson:"description"`
+			DefaultLanguage string      `bson:"defaultLanguage"`
+		}
+		if err := cursor.Decode(&video); err != nil {
+			log.Errorf("MigrateTextLanguage: Error decoding video: %v", err)
+			continue
+		}
+		lang := textLanguage(entities.Video{
+			Title:           video.Title,
+			Description:     video.Description,
+			DefaultLanguage: video.DefaultLanguage,
+		})
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": video.Id}).
+			SetUpdate(bson.M{"$set": bson.M{textLanguageField: lang}}))
+	}
+	if len(models) == 0 {
+		return
+	}
+
+	res, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		log.Fatalf("MigrateTextLanguage: Error setting text language: %v", err)
+	}
+	log.Infof("MigrateTextLanguage: Set text language of %v documents", res.ModifiedCount)
+}
@@ -0,0 +1,139 @@
+package get_video-search_video
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrVideoNotFound = errors.New("video not found")
+
+// Creates the unique index on uniqueId which the upserts of bulkInsert and the lookups of videos by
+// their YouTube id rely on
+func CreateUniqueIdIndex() {
+	model := mongo.IndexModel{
+		Keys:    bson.D{{Key: "uniqueId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), model, options)
+	if err != nil {
+		log.Fatalf("CreateUniqueIdIndex: Error creating index: %v", err)
+	}
+}
+
+// Deletes the videos stored more than once by the inserts made before the unique index on uniqueId,
+// keeping the first stored one, so that the index can be created
+func MigrateDuplicateUniqueIds() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$uniqueId",
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		log.Fatalf("MigrateDuplicateUniqueIds: Error finding duplicate videos: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	duplicates := make([]interface{}, 0)
+	for cursor.Next(ctx) {
+		var group struct {
+			Ids []interface{} `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			log.Errorf("MigrateDuplicateUniqueIds: Error decoding duplicate videos: %v", err)
+			continue
+		}
+		duplicates = append(duplicates, group.Ids[1:]...)
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("MigrateDuplicateUniqueIds: Error finding duplicate videos: %v", err)
+	}
+	if len(duplicates) == 0 {
+		return
+	}
+
+	res, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": duplicates}})
+	if err != nil {
+		log.Fatalf("MigrateDuplicateUniqueIds: Error deleting duplicate videos: %v", err)
+	}
+	log.Infof("MigrateDuplicateUniqueIds: Deleted %v duplicate videos", res.DeletedCount)
+}
+
+// Returns the stored video with the given YouTube id
+func getVideoByUniqueId(ctx context.Context, uniqueId string) (entities.Video, error) {
+	var video entities.Video
+	err := collection.FindOne(ctx, bson.M{"uniqueId": uniqueId}).Decode(&video)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return video, ErrVideoNotFound
+	}
+	return video, err
+}
+
+// Returns the stored video with the given YouTube id
+func GetVideo(uniqueId string) (entities.Video, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	video, err := getVideoByUniqueId(ctx, uniqueId)
+	if err != nil && !errors.Is(err, ErrVideoNotFound) {
+		log.Errorf("GetVideo: Error fetching video: %v", err)
+	}
+	return video, err
+}
+
+// Returns the stored videos with the given YouTube ids in the order of the ids, along with the ids
+// which aren't stored. Duplicate ids are looked up once.
+func BatchGetVideos(uniqueIds []string) ([]entities.Video, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"uniqueId": bson.M{"$in": uniqueIds}})
+	if err != nil {
+		log.Errorf("BatchGetVideos: Error fetching videos: %v", err)
+		return nil, nil, err
+	}
+	var results []entities.Video
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Errorf("BatchGetVideos: Error decoding videos: %v", err)
+		return nil, nil, err
+	}
+
+	stored := make(map[string]entities.Video, len(results))
+	for _, video := range results {
+		stored[video.UniqueId] = video
+	}
+
+	videos := make([]entities.Video, 0, len(results))
+	notFound := []string{}
+	seen := make(map[string]bool, len(uniqueIds))
+	for _, uniqueId := range uniqueIds {
+		if seen[uniqueId] {
+			continue
+		}
+		seen[uniqueId] = true
+		if video, ok := stored[uniqueId]; ok {
+			videos = append(videos, video)
+		} else {
+			notFound = append(notFound, uniqueId)
+		}
+	}
+	return videos, notFound, nil
+}
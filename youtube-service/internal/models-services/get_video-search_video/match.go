@@ -0,0 +1,111 @@
+package get_video-search_video
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/pkg/highlight"
+	"github.com/youtube-service/pkg/language"
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+// Reports whether a video, such as a newly inserted one, matches a search query the same way a
+// search would find it: field scoped terms, phrases and OR groups must match, negated terms must
+// not, and when the query only has plain words any of them must match. Words are stemmed in the
+// language of the video like the text index does.
+func MatchesQuery(video entities.Video, q searchquery.Query) bool {
+	stemmer := language.StemmerLanguage(video.TextLanguage)
+	words := make([]string, 0)
+	hasPhrase := false
+
+	for _, group := range q.Groups {
+		if plainWords(group) {
+			for _, term := range group {
+				words = append(words, term.Value)
+			}
+			continue
+		}
+		matched := false
+		for _, term := range group {
+			if termMatches(video, term, stemmer) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+		if len(group) == 1 && group[0].Field == "" && group[0].Phrase && !group[0].Negated {
+			hasPhrase = true
+		}
+	}
+
+	// Like in a text search, words only rank the videos containing the phrases of the query
+	if len(words) == 0 || hasPhrase {
+		return true
+	}
+	terms := highlight.NewTerms(stemmer, words...)
+	for _, text := range append([]string{video.Title, video.Description}, video.Tags...) {
+		if _, ok := highlight.Highlight(text, terms, "", ""); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether the video contains the term in its field, or not if the term is negated
+func termMatches(video entities.Video, term searchquery.Term, stemmer string) bool {
+	var texts []string
+	switch term.Field {
+	case searchquery.FieldTitle:
+		texts = []string{video.Title}
+	case searchquery.FieldDescription:
+		texts = []string{video.Description}
+	case searchquery.FieldChannel:
+		if video.ChannelId == term.Value {
+			return !term.Negated
+		}
+		texts = []string{video.ChannelTitle}
+	default:
+		texts = append([]string{video.Title, video.Description}, video.Tags...)
+	}
+
+	found := false
+	if term.Field == "" && !term.Phrase {
+		terms := highlight.NewTerms(stemmer, term.Value)
+		for _, text := range texts {
+			if _, ok := highlight.Highlight(text, terms, "", ""); ok {
+				found = true
+				break
+			}
+		}
+	} else {
+		regex := regexp.MustCompile("(?i)" + termPattern(term))
+		found = regex.MatchString(strings.Join(texts, "\n"))
+	}
+	return found != term.Negated
+}
+
+// Reports whether a video passes the filter the same way as the mongo filter of filterQuery
+func matchesFilter(video entities.Video, videoFilter entities.VideoFilter) bool {
+	switch {
+	case videoFilter.PublishedAfter != nil && video.PublishedAt.Before(*videoFilter.PublishedAfter):
+		return false
+	case videoFilter.PublishedBefore != nil && !video.PublishedAt.Before(*videoFilter.PublishedBefore):
+		return false
+	case videoFilter.ChannelId != "" && video.ChannelId != videoFilter.ChannelId:
+		return false
+	case videoFilter.SourceQuery != "" && video.SourceQuery != videoFilter.SourceQuery:
+		return false
+	case videoFilter.DurationBucket != "" && video.DurationBucket != videoFilter.DurationBucket:
+		return false
+	case videoFilter.MinViewCount > 0 && video.ViewCount < videoFilter.MinViewCount:
+		return false
+	case videoFilter.DefaultLanguage != "" && video.DefaultLanguage != videoFilter.DefaultLanguage:
+		return false
+	case videoFilter.LiveBroadcastContent != "" && video.LiveBroadcastContent != videoFilter.LiveBroadcastContent:
+		return false
+	}
+	return true
+}
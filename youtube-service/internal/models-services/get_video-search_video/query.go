@@ -0,0 +1,131 @@
+package get_video-search_video
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/youtube-service/pkg/searchquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Translates a parsed search query into the $search string of the text index and the
+// conditions which the text index cannot express: field scoped terms and OR groups
+// of anything but plain words. Returns an empty search string if no term can use the text index.
+func searchQueryFilter(q searchquery.Query) (string, bson.A) {
+	positive := make([]string, 0)
+	negated := make([]searchquery.Term, 0)
+	conditions := bson.A{}
+
+	for _, group := range q.Groups {
+		if len(group) == 1 {
+			term := group[0]
+			switch {
+			case term.Field != "":
+				conditions = append(conditions, termCondition(term))
+			case term.Negated:
+				negated = append(negated, term)
+			default:
+				positive = append(positive, textTerm(term))
+			}
+			continue
+		}
+
+		// Plain words are already alternatives in a text search
+		if plainWords(group) {
+			for _, term := range group {
+				positive = append(positive, term.Value)
+			}
+			continue
+		}
+		alternatives := bson.A{}
+		for _, term := range group {
+			alternatives = append(alternatives, termCondition(term))
+		}
+		conditions = append(conditions, bson.M{"$or": alternatives})
+	}
+
+	// A text search with only negated terms matches nothing, so they are
+	// only added to the text search if it has terms to match
+	if len(positive) == 0 {
+		for _, term := range negated {
+			conditions = append(conditions, termCondition(term))
+		}
+		return "", conditions
+	}
+	for _, term := range negated {
+		positive = append(positive, textTerm(term))
+	}
+	return strings.Join(positive, " "), conditions
+}
+
+func plainWords(group []searchquery.Term) bool {
+	for _, term := range group {
+		if term.Field != "" || term.Phrase || term.Negated {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the term in the syntax of the $search string of a text search
+func textTerm(term searchquery.Term) string {
+	value := term.Value
+	if term.Phrase {
+		value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	if term.Negated {
+		value = "-" + value
+	}
+	return value
+}
+
+// Returns the regular expression matching the term as whole words, in which the words of phrases can
+// be separated by any whitespace, e.g. title:live matches "Live now" but not "olive". Word boundaries
+// are ASCII in both mongo and Go, so they are only added next to ASCII letters, digits and _.
+func termPattern(term searchquery.Term) string {
+	pattern := regexp.QuoteMeta(term.Value)
+	if term.Phrase {
+		pattern = strings.ReplaceAll(pattern, " ", `\s+`)
+	}
+	if term.Value == "" {
+		return pattern
+	}
+	if isWordByte(term.Value[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(term.Value[len(term.Value)-1]) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// Returns a case insensitive condition matching the term in its field, or in the title
+// and description if it is not scoped to a field
+func termCondition(term searchquery.Term) bson.M {
+	regex := primitive.Regex{Pattern: termPattern(term), Options: "i"}
+
+	var matches bson.A
+	switch term.Field {
+	case searchquery.FieldTitle:
+		matches = bson.A{bson.M{"title": regex}}
+	case searchquery.FieldDescription:
+		matches = bson.A{bson.M{"description": regex}}
+	case searchquery.FieldChannel:
+		matches = bson.A{bson.M{"channelTitle": regex}, bson.M{"channelId": term.Value}}
+	default:
+		matches = bson.A{bson.M{"title": regex}, bson.M{"description": regex}}
+	}
+
+	if term.Negated {
+		return bson.M{"$nor": matches}
+	}
+	if len(matches) == 1 {
+		return matches[0].(bson.M)
+	}
+	return bson.M{"$or": matches}
+}
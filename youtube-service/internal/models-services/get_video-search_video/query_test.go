@@ -0,0 +1,37 @@
+package get_video-search_video
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/youtube-service/pkg/searchquery"
+)
+
+func TestTermPatternMatchesWholeWords(t *testing.T) {
+	tests := []struct {
+		term    searchquery.Term
+		text    string
+		matches bool
+	}{
+		{searchquery.Term{Field: searchquery.FieldTitle, Value: "live"}, "Live: India vs Australia", true},
+		{searchquery.Term{Field: searchquery.FieldTitle, Value: "live"}, "watch it (live)", true},
+		{searchquery.Term{Field: searchquery.FieldTitle, Value: "live"}, "olive oil", false},
+		{searchquery.Term{Field: searchquery.FieldTitle, Value: "live"}, "fast delivery", false},
+		{searchquery.Term{Field: searchquery.FieldTitle, Value: "live"}, "lively crowd", false},
+		{searchquery.Term{Value: "ind vs aus", Phrase: true}, "IND  vs\nAUS highlights", true},
+		{searchquery.Term{Value: "ind vs aus", Phrase: true}, "kind vs aussies", false},
+		{searchquery.Term{Value: "c++"}, "learn C++ today", true},
+		{searchquery.Term{Value: "c++"}, "learn abc++ today", false},
+		{searchquery.Term{Value: "#ipl2022"}, "best of #IPL2022", true},
+		{searchquery.Term{Value: "#ipl2022"}, "best of #IPL20223", false},
+		{searchquery.Term{Value: "café"}, "un café noir", true},
+	}
+	for _, test := range tests {
+		t.Run(test.term.String()+" in "+test.text, func(t *testing.T) {
+			regex := regexp.MustCompile("(?i)" + termPattern(test.term))
+			if got := regex.MatchString(test.text); got != test.matches {
+				t.Errorf("termPattern(%v) = %q matches %q: %v, want %v", test.term, regex.String(), test.text, got, test.matches)
+			}
+		})
+	}
+}
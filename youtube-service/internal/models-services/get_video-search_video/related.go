@@ -0,0 +1,212 @@
+package get_video-search_video
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/pkg/language"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of candidates fetched by text similarity and from the same channel
+	relatedTextCandidates    = 100
+	relatedChannelCandidates = 30
+	// Max number of terms of the source video searched for similar videos, of which at most
+	// relatedDescriptionTerms are the most frequent terms of its description
+	relatedMaxTerms         = 30
+	relatedDescriptionTerms = 10
+
+	// Weights of the signals blended into the similarity of a related video
+	relatedTextWeight    = 0.5
+	relatedChannelWeight = 0.2
+	relatedTagsWeight    = 0.2
+	relatedTimeWeight    = 0.1
+	// Publish time distance at which the time proximity of two videos halves
+	relatedTimeHalfLife = 7 * 24 * time.Hour
+)
+
+// Returns the search string of the words of the title and the tags of a video followed by the most
+// frequent words of its description. Stop words are left out of the description words, as they are
+// only dropped by the text search of languages mongo supports, and so are numbers and short words.
+func relatedSearch(video entities.Video) string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0, relatedMaxTerms)
+	for _, term := range strings.Fields(normalizeForSuggest(video.Title + " " + strings.Join(video.Tags, " "))) {
+		if len(terms) == relatedMaxTerms-relatedDescriptionTerms {
+			break
+		}
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	added := 0
+	for _, term := range descriptionTerms(video) {
+		if added == relatedDescriptionTerms || len(terms) == relatedMaxTerms {
+			break
+		}
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+			added++
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// Returns the distinct words of the description of the video, the most frequent first and in order
+// of appearance among equally frequent ones, without stop words
+func descriptionTerms(video entities.Video) []string {
+	counts := make(map[string]int)
+	order := make([]string, 0)
+	for _, term := range strings.Fields(normalizeForSuggest(video.Description)) {
+		if utf8.RuneCountInString(term) < minDictionaryTermLength || isNumber(term) ||
+			language.IsStopWord(video.TextLanguage, term) {
+			continue
+		}
+		if counts[term] == 0 {
+			order = append(order, term)
+		}
+		counts[term]++
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	return order
+}
+
+func isNumber(term string) bool {
+	for _, r := range term {
+		if !unicode.IsNumber(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the share of the tags of a and b which they have in common
+func tagOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	tags := make(map[string]bool, len(a))
+	for _, tag := range a {
+		tags[strings.ToLower(tag)] = true
+	}
+	common := 0
+	union := len(tags)
+	for _, tag := range b {
+		if tags[strings.ToLower(tag)] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// Returns the videos most similar to the stored video with the given YouTube id. Candidates sharing
+// its title, tag and top description terms according to the text index and videos of the same
+// channel are ranked by a blend of their text score, whether they share the channel, their tag
+// overlap and how close their publish time is.
+func RelatedVideos(uniqueId string, limit int64) ([]entities.Video, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	video, err := getVideoByUniqueId(ctx, uniqueId)
+	if err != nil {
+		if !errors.Is(err, ErrVideoNotFound) {
+			log.Errorf("RelatedVideos: Error fetching video: %v", err)
+		}
+		return nil, err
+	}
+
+	candidates := make(map[string]entities.Video)
+	maxTextScore := 0.0
+
+	if search := relatedSearch(video); search != "" {
+		text := bson.M{"$search": search}
+		if video.TextLanguage != "" {
+			text["$language"] = language.TextSearchLanguage(video.TextLanguage)
+		}
+		findOptions := options.Find().
+			SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}, "titlePrefixes": 0}).
+			SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetLimit(relatedTextCandidates)
+		cursor, err := collection.Find(ctx, bson.M{"$text": text, "uniqueId": bson.M{"$ne": uniqueId}}, findOptions)
+		if err != nil {
+			log.Errorf("RelatedVideos: Error searching similar videos: %v", err)
+			return nil, err
+		}
+		var results []entities.Video
+		if err := cursor.All(ctx, &results); err != nil {
+			log.Errorf("RelatedVideos: Error decoding similar videos: %v", err)
+			return nil, err
+		}
+		for _, result := range results {
+			candidates[result.UniqueId] = result
+			maxTextScore = math.Max(maxTextScore, result.Score)
+		}
+	}
+
+	if video.ChannelId != "" {
+		findOptions := options.Find().
+			SetProjection(bson.M{"titlePrefixes": 0}).
+			SetSort(bson.D{{Key: "publishedAt", Value: -1}}).
+			SetLimit(relatedChannelCandidates)
+		cursor, err := collection.Find(ctx, bson.M{"channelId": video.ChannelId, "uniqueId": bson.M{"$ne": uniqueId}}, findOptions)
+		if err != nil {
+			log.Errorf("RelatedVideos: Error fetching videos of the channel: %v", err)
+			return nil, err
+		}
+		var results []entities.Video
+		if err := cursor.All(ctx, &results); err != nil {
+			log.Errorf("RelatedVideos: Error decoding videos of the channel: %v", err)
+			return nil, err
+		}
+		for _, result := range results {
+			if _, ok := candidates[result.UniqueId]; !ok {
+				candidates[result.UniqueId] = result
+			}
+		}
+	}
+
+	related := make([]entities.Video, 0, len(candidates))
+	for _, candidate := range candidates {
+		similarity := 0.0
+		if maxTextScore > 0 {
+			similarity += relatedTextWeight * candidate.Score / maxTextScore
+		}
+		if video.ChannelId != "" && candidate.ChannelId == video.ChannelId {
+			similarity += relatedChannelWeight
+		}
+		similarity += relatedTagsWeight * tagOverlap(video.Tags, candidate.Tags)
+		distance := math.Abs(float64(video.PublishedAt.Sub(candidate.PublishedAt)))
+		similarity += relatedTimeWeight * math.Pow(0.5, distance/float64(relatedTimeHalfLife))
+
+		candidate.Score = similarity
+		related = append(related, candidate)
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].UniqueId < related[j].UniqueId
+	})
+	if int64(len(related)) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
@@ -0,0 +1,34 @@
+package get_video-search_video
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/youtube-service/internal/entities"
+)
+
+func TestRelatedSearch(t *testing.T) {
+	video := entities.Video{
+		Title: "India vs Australia highlights",
+		Tags:  []string{"cricket", "India"},
+		Description: "Watch the highlights of the 3rd T20 between India and Australia at Hyderabad. " +
+			"Kohli and Suryakumar starred as India won the T20 series 2-1. " +
+			"Subscribe for more cricket: https://www.youtube.com/c/cricket 2022",
+		TextLanguage: "en",
+	}
+	want := "india vs australia highlights cricket t20 3rd hyderabad kohli suryakumar starred won series"
+	if got := relatedSearch(video); got != want {
+		t.Errorf("relatedSearch() = %q, want %q", got, want)
+	}
+}
+
+func TestDescriptionTerms(t *testing.T) {
+	video := entities.Video{
+		Description:  "Resumen del partido: el Real Madrid gana. Resumen completo del Real Madrid en 2022.",
+		TextLanguage: "es",
+	}
+	want := []string{"resumen", "real", "madrid", "partido", "gana", "completo"}
+	if got := descriptionTerms(video); !reflect.DeepEqual(got, want) {
+		t.Errorf("descriptionTerms() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package get_video-search_video
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/pkg/searchquery"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	textIndexName = "title_description_tags_text"
+	// Field overriding the language in which a document is indexed. The default "language"
+	// field is not used as mongo rejects documents whose language it does not support.
+	textLanguageField = "textLanguage"
+)
+
+// Returns the configured weights of the text indexed fields
+func textIndexWeights() bson.D {
+	return bson.D{
+		{Key: "title", Value: configs.GetTitleWeight()},
+		{Key: "description", Value: configs.GetDescriptionWeight()},
+		{Key: "tags", Value: configs.GetTagsWeight()},
+	}
+}
+
+// Drops the text index of the collection if its name, weights or language override differ from the expected ones.
+// A collection can only have one text index, so it has to be dropped before creating a new one.
+// Returns true if an index with the expected weights already exists.
+func dropTextIndexIfChanged(weights bson.D) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return false, err
+	}
+
+	for _, index := range indexes {
+		key, _ := index["key"].(bson.M)
+		if key["_fts"] != "text" {
+			continue
+		}
+		name, _ := index["name"].(string)
+		existing, _ := index["weights"].(bson.M)
+		if name == textIndexName && sameWeights(existing, weights) && index["language_override"] == textLanguageField {
+			return true, nil
+		}
+
+		log.Infof("dropTextIndexIfChanged: Text index options changed. Re-creating index %v", name)
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func sameWeights(existing bson.M, weights bson.D) bool {
+	if len(existing) != len(weights) {
+		return false
+	}
+	for _, weight := range weights {
+		if toInt64(existing[weight.Key]) != weight.Value.(int64) {
+			return false
+		}
+	}
+	return true
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	default:
+		return 0
+	}
+}
+
+// Explains the score of a search result: the text score given by mongo, the recency multiplier
+// blended into it and the query terms found in each text indexed field weighted by the field weight.
+// Terms are matched without stemming, so stemmed matches are counted in the text score only.
+func scoreBreakdown(query searchquery.Query, video entities.Video, halfLifeHours float64, now *time.Time) *entities.ScoreBreakdown {
+	breakdown := &entities.ScoreBreakdown{
+		Score:             video.Score,
+		TextScore:         video.TextScore,
+		RecencyMultiplier: 1,
+		MinScore:          configs.GetMinSearchScore(),
+		Fields:            make([]entities.FieldMatch, 0, 3),
+	}
+	if now != nil && halfLifeHours > 0 && video.TextScore > 0 {
+		breakdown.RecencyMultiplier = video.Score / video.TextScore
+	}
+
+	terms := query.PositiveTerms()
+	fields := []struct {
+		name   string
+		text   string
+		weight int64
+	}{
+		{"title", video.Title, configs.GetTitleWeight()},
+		{"description", video.Description, configs.GetDescriptionWeight()},
+		{"tags", strings.Join(video.Tags, " "), configs.GetTagsWeight()},
+	}
+	for _, field := range fields {
+		text := strings.ToLower(field.text)
+		matched := make([]string, 0)
+		for _, term := range terms {
+			if term.Field != "" && term.Field != field.name {
+				continue
+			}
+			if strings.Contains(text, strings.ToLower(term.Value)) {
+				matched = append(matched, term.String())
+			}
+		}
+		breakdown.Fields = append(breakdown.Fields, entities.FieldMatch{
+			Field:        field.name,
+			Weight:       field.weight,
+			MatchedTerms: matched,
+		})
+	}
+	return breakdown
+}
@@ -0,0 +1,46 @@
+package get_video-search_video
+
+import (
+	"errors"
+
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// sortOrder is the document field and direction videos are sorted on
+type sortOrder struct {
+	name      string
+	field     string
+	direction int
+}
+
+var sortOrders = map[string]sortOrder{
+	entities.SortRelevance: {name: entities.SortRelevance, field: "score", direction: -1},
+	entities.SortNewest:    {name: entities.SortNewest, field: "publishedAt", direction: -1},
+	entities.SortOldest:    {name: entities.SortOldest, field: "publishedAt", direction: 1},
+	entities.SortViews:     {name: entities.SortViews, field: "viewCount", direction: -1},
+	entities.SortLikes:     {name: entities.SortLikes, field: "likeCount", direction: -1},
+	entities.SortTrending:  {name: entities.SortTrending, field: "trending.score", direction: -1},
+}
+
+// Returns the sort order with the given name, or the default one if name is empty
+func getSortOrder(name string, defaultName string) (sortOrder, error) {
+	if name == "" {
+		name = defaultName
+	}
+	order, ok := sortOrders[name]
+	if !ok {
+		return sortOrder{}, ErrInvalidSort
+	}
+	return order, nil
+}
+
+// Returns the mongo sort document of the sort order with _id as tie breaker
+func (order sortOrder) sort() bson.D {
+	return bson.D{
+		{Key: order.field, Value: order.direction},
+		{Key: "_id", Value: order.direction},
+	}
+}
@@ -0,0 +1,235 @@
+package get_video-search_video
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/pkg/searchquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Search queries made by clients, used to suggest popular queries
+var queriesCollection *mongo.Collection
+
+// Lowercases the text and replaces punctuation and repeated spaces by a single space
+// so that suggestions match regardless of case and punctuation
+func normalizeForSuggest(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Returns the edge n-grams of the normalized text starting at each word, up to the configured
+// max prefix length, e.g. "ind vs" gives i, in, ind, "ind ", "ind v", "ind vs", v, vs
+func suggestPrefixes(s string) []string {
+	runes := []rune(normalizeForSuggest(s))
+	maxLength := int(configs.GetSuggestMaxPrefixLength())
+
+	seen := make(map[string]bool)
+	prefixes := make([]string, 0)
+	for start := range runes {
+		if start > 0 && runes[start-1] != ' ' {
+			continue
+		}
+		for end := start + 1; end <= len(runes) && end-start <= maxLength; end++ {
+			prefix := string(runes[start:end])
+			if !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
+
+// Returns the value used to look up a prefix in the prefix indexes
+func lookupPrefix(prefix string) string {
+	runes := []rune(normalizeForSuggest(prefix))
+	if maxLength := int(configs.GetSuggestMaxPrefixLength()); len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	return string(runes)
+}
+
+// Returns true if a word of the normalized text starts with the normalized prefix.
+// Used to filter out matches of prefixes longer than the indexed ones.
+func hasWordPrefix(text string, prefix string) bool {
+	text = normalizeForSuggest(text)
+	return strings.HasPrefix(text, prefix) || strings.Contains(text, " "+prefix)
+}
+
+// Creates the indexes of the title prefixes of videos and of the past search queries
+func CreateSuggestIndexes() {
+	titlePrefixesModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "titlePrefixes", Value: 1},
+			{Key: "viewCount", Value: -1},
+		},
+	}
+	queriesModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "query", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "prefixes", Value: 1},
+				{Key: "count", Value: -1},
+			},
+		},
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), titlePrefixesModel, options)
+	if err != nil {
+		log.Fatalf("CreateSuggestIndexes: Error creating title prefixes index: %v", err)
+	}
+
+	_, err = queriesCollection.Indexes().CreateMany(context.TODO(), queriesModels, options)
+	if err != nil {
+		log.Fatalf("CreateSuggestIndexes: Error creating search queries indexes: %v", err)
+	}
+}
+
+// Sets the title prefixes of videos stored before they were maintained on insert
+func MigrateTitlePrefixes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"titlePrefixes": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"title": 1}))
+	if err != nil {
+		log.Fatalf("MigrateTitlePrefixes: Error finding videos: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	models := make([]mongo.WriteModel, 0)
+	for cursor.Next(ctx) {
+		var video struct {
+			Id    interface{} `bson:"_id"`
+			Title string      `bson:"title"`
+		}
+		if err := cursor.Decode(&video); err != nil {
+			log.Errorf("MigrateTitlePrefixes: Error decoding video: %v", err)
+			continue
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": video.Id}).
+			SetUpdate(bson.M{"$set": bson.M{"titlePrefixes": suggestPrefixes(video.Title)}}))
+	}
+	if len(models) == 0 {
+		return
+	}
+
+	res, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		log.Fatalf("MigrateTitlePrefixes: Error setting title prefixes: %v", err)
+	}
+	log.Infof("MigrateTitlePrefixes: Set title prefixes of %v documents", res.ModifiedCount)
+}
+
+// Records a search query so that it can be suggested to other clients.
+// Only the terms which are not negated are recorded, without their field prefix, since the syntax is
+// stripped from suggestions and -highlights would otherwise be suggested as highlights.
+// Errors are only logged as they should not fail the search.
+func RecordSearchQuery(query searchquery.Query) {
+	terms := query.PositiveTerms()
+	values := make([]string, 0, len(terms))
+	for _, term := range terms {
+		values = append(values, term.Value)
+	}
+	normalized := normalizeForSuggest(strings.Join(values, " "))
+	if normalized == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$set":         bson.M{"lastSearchedAt": time.Now()},
+		"$setOnInsert": bson.M{"prefixes": suggestPrefixes(normalized)},
+	}
+	_, err := queriesCollection.UpdateOne(ctx, bson.M{"query": normalized}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Errorf("RecordSearchQuery: Error recording search query: %v", err)
+	}
+}
+
+// Returns the titles of the most viewed videos and the most popular past search queries
+// having a word starting with the prefix
+func Suggest(prefix string, limit int64) ([]string, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	normalized := normalizeForSuggest(prefix)
+	lookup := lookupPrefix(prefix)
+
+	// More documents than needed are fetched as duplicate titles and matches
+	// of truncated prefixes are filtered out
+	titleCursor, err := collection.Find(ctx, bson.M{"titlePrefixes": lookup}, options.Find().
+		SetSort(bson.D{{Key: "viewCount", Value: -1}}).
+		SetProjection(bson.M{"title": 1}).
+		SetLimit(limit*4))
+	if err != nil {
+		log.Errorf("Suggest: Error finding titles: %v", err)
+		return nil, nil, err
+	}
+	defer titleCursor.Close(ctx)
+
+	titles := make([]string, 0, limit)
+	seen := make(map[string]bool)
+	for titleCursor.Next(ctx) && int64(len(titles)) < limit {
+		var video struct {
+			Title string `bson:"title"`
+		}
+		if err := titleCursor.Decode(&video); err != nil {
+			log.Errorf("Suggest: Error decoding title: %v", err)
+			continue
+		}
+		if seen[video.Title] || !hasWordPrefix(video.Title, normalized) {
+			continue
+		}
+		seen[video.Title] = true
+		titles = append(titles, video.Title)
+	}
+
+	queryCursor, err := queriesCollection.Find(ctx, bson.M{"prefixes": lookup}, options.Find().
+		SetSort(bson.D{{Key: "count", Value: -1}}).
+		SetProjection(bson.M{"query": 1}).
+		SetLimit(limit*2))
+	if err != nil {
+		log.Errorf("Suggest: Error finding search queries: %v", err)
+		return nil, nil, err
+	}
+	defer queryCursor.Close(ctx)
+
+	queries := make([]string, 0, limit)
+	for queryCursor.Next(ctx) && int64(len(queries)) < limit {
+		var query struct {
+			Query string `bson:"query"`
+		}
+		if err := queryCursor.Decode(&query); err != nil {
+			log.Errorf("Suggest: Error decoding search query: %v", err)
+			continue
+		}
+		if !hasWordPrefix(query.Query, normalized) {
+			continue
+		}
+		queries = append(queries, query.Query)
+	}
+	return titles, queries, nil
+}
@@ -0,0 +1,249 @@
+package get_video-search_video
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Time-series collection of the view and like counts of videos over time
+var statsCollection *mongo.Collection
+
+const (
+	// Snapshots older than this are deleted by mongo
+	statsRetention = 7 * 24 * time.Hour
+	// Max number of ids of a videos.list call
+	ytVideosListMaxIds = 50
+)
+
+// Statistics of a video at a point in time
+type statsSnapshot struct {
+	UniqueId  string    `bson:"uniqueId"`
+	TakenAt   time.Time `bson:"takenAt"`
+	ViewCount int64     `bson:"viewCount"`
+	LikeCount int64     `bson:"likeCount"`
+}
+
+// Creates the time-series collection of statistics snapshots if it does not exist, and the index
+// used to rank trending videos
+func CreateTrendingIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	timeSeries := options.TimeSeries().
+		SetTimeField("takenAt").
+		SetMetaField("uniqueId").
+		SetGranularity("hours")
+	collectionOptions := options.CreateCollection().
+		SetTimeSeriesOptions(timeSeries).
+		SetExpireAfterSeconds(int64(statsRetention.Seconds()))
+	err := statsCollection.Database().CreateCollection(ctx, statsCollection.Name(), collectionOptions)
+	var commandErr mongo.CommandError
+	if err != nil && !(errors.As(err, &commandErr) && commandErr.Name == "NamespaceExists") {
+		log.Fatalf("CreateTrendingIndexes: Error creating stats collection: %v", err)
+	}
+
+	model := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "trending.score", Value: -1},
+			{Key: "_id", Value: -1},
+		},
+		Options: options.Index().SetSparse(true),
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err = collection.Indexes().CreateOne(ctx, model, options)
+	if err != nil {
+		log.Fatalf("CreateTrendingIndexes: Error creating index: %v", err)
+	}
+}
+
+// Fetches the current statistics of the most recently published videos, updates their view and
+// like counts and stores a snapshot of them. Skipped until a valid API key has been set by the
+// job fetching new videos.
+func SnapshotVideoStats() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	key := configs.GetValidApiKey()
+	if key == "" {
+		log.Info("SnapshotVideoStats: No valid API key yet. Skipping snapshot.")
+		return nil
+	}
+
+	findOptions := options.Find().
+		SetProjection(bson.M{"uniqueId": 1}).
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(configs.GetTrendingMaxTrackedVideos())
+	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		log.Errorf("SnapshotVideoStats: Error fetching videos: %v", err)
+		return err
+	}
+	var videos []entities.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		log.Errorf("SnapshotVideoStats: Error decoding videos: %v", err)
+		return err
+	}
+	if len(videos) == 0 {
+		return nil
+	}
+
+	youtubeService, err := youtube.NewService(ctx, option.WithAPIKey(key))
+	if err != nil {
+		log.Errorf("SnapshotVideoStats: Error creating new service: %v", err)
+		return err
+	}
+
+	takenAt := time.Now().UTC()
+	snapshots := make([]interface{}, 0, len(videos))
+	models := make([]mongo.WriteModel, 0, len(videos))
+	for start := 0; start < len(videos); start += ytVideosListMaxIds {
+		end := start + ytVideosListMaxIds
+		if end > len(videos) {
+			end = len(videos)
+		}
+		ids := make([]string, 0, end-start)
+		for _, video := range videos[start:end] {
+			ids = append(ids, video.UniqueId)
+		}
+
+		response, err := youtubeService.Videos.List([]string{"statistics"}).
+			Id(ids...).
+			MaxResults(int64(len(ids))).
+			Context(ctx).
+			Do()
+		if err != nil {
+			if strings.Contains(err.Error(), "quotaExceeded") {
+				log.Info("SnapshotVideoStats: Quota exceeded. Skipping snapshot.")
+			} else {
+				log.Errorf("SnapshotVideoStats: Error fetching statistics: %v", err)
+			}
+			return err
+		}
+
+		for _, item := range response.Items {
+			if item.Statistics == nil {
+				continue
+			}
+			snapshot := statsSnapshot{
+				UniqueId:  item.Id,
+				TakenAt:   takenAt,
+				ViewCount: int64(item.Statistics.ViewCount),
+				LikeCount: int64(item.Statistics.LikeCount),
+			}
+			snapshots = append(snapshots, snapshot)
+			update := bson.M{"$set": bson.M{"viewCount": snapshot.ViewCount, "likeCount": snapshot.LikeCount}}
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(bson.M{"uniqueId": item.Id}).SetUpdate(update))
+		}
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	if _, err := statsCollection.InsertMany(ctx, snapshots); err != nil {
+		log.Errorf("SnapshotVideoStats: Error inserting snapshots: %v", err)
+		return err
+	}
+	if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		log.Errorf("SnapshotVideoStats: Error updating statistics: %v", err)
+		return err
+	}
+	log.Infof("SnapshotVideoStats: Stored statistics of %v videos", len(snapshots))
+	return nil
+}
+
+// Computes how fast the views and likes of each video grew between its first and last snapshot of
+// the trending window and stores it in the trending field of the video. The trending field of
+// videos without growth in the window is removed.
+func ComputeTrending() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now().UTC()
+	windowStart := now.Add(-time.Duration(configs.GetTrendingWindowHours()) * time.Hour)
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"takenAt": bson.M{"$gte": windowStart}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "uniqueId", Value: 1}, {Key: "takenAt", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":        "$uniqueId",
+			"firstAt":    bson.M{"$first": "$takenAt"},
+			"lastAt":     bson.M{"$last": "$takenAt"},
+			"firstViews": bson.M{"$first": "$viewCount"},
+			"lastViews":  bson.M{"$last": "$viewCount"},
+			"firstLikes": bson.M{"$first": "$likeCount"},
+			"lastLikes":  bson.M{"$last": "$likeCount"},
+		}}},
+		bson.D{{Key: "$match", Value: bson.M{"$expr": bson.M{"$gt": bson.A{"$lastAt", "$firstAt"}}}}},
+	}
+	cursor, err := statsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Errorf("ComputeTrending: Error aggregating snapshots: %v", err)
+		return err
+	}
+	var growths []struct {
+		UniqueId   string    `bson:"_id"`
+		FirstAt    time.Time `bson:"firstAt"`
+		LastAt     time.Time `bson:"lastAt"`
+		FirstViews int64     `bson:"firstViews"`
+		LastViews  int64     `bson:"lastViews"`
+		FirstLikes int64     `bson:"firstLikes"`
+		LastLikes  int64     `bson:"lastLikes"`
+	}
+	if err := cursor.All(ctx, &growths); err != nil {
+		log.Errorf("ComputeTrending: Error decoding snapshots: %v", err)
+		return err
+	}
+
+	models := make([]mongo.WriteModel, 0, len(growths))
+	for _, growth := range growths {
+		hours := growth.LastAt.Sub(growth.FirstAt).Hours()
+		// Counts can be corrected downwards by YouTube, which is not negative growth
+		trending := entities.Trending{
+			ViewVelocity: math.Max(0, float64(growth.LastViews-growth.FirstViews)/hours),
+			LikeVelocity: math.Max(0, float64(growth.LastLikes-growth.FirstLikes)/hours),
+			ComputedAt:   now,
+		}
+		trending.Score = trending.ViewVelocity + configs.GetTrendingLikeWeight()*trending.LikeVelocity
+		if trending.Score == 0 {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"trending": trending}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(bson.M{"uniqueId": growth.UniqueId}).SetUpdate(update))
+	}
+	if len(models) > 0 {
+		if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			log.Errorf("ComputeTrending: Error updating trending videos: %v", err)
+			return err
+		}
+	}
+
+	_, err = collection.UpdateMany(ctx, bson.M{"trending.computedAt": bson.M{"$lt": now}}, bson.M{"$unset": bson.M{"trending": ""}})
+	if err != nil {
+		log.Errorf("ComputeTrending: Error removing stale trending videos: %v", err)
+		return err
+	}
+	log.Infof("ComputeTrending: %v videos are trending", len(models))
+	return nil
+}
+
+// Get trending videos matching the filter from the database in paginated format, fastest growing first.
+// If a cursor is requested, videos after the cursor are returned and the page number is ignored.
+func GetTrendingVideos(req entities.PageRequest, videoFilter entities.VideoFilter) (entities.VideoPage, error) {
+	filter := filterQuery(videoFilter)
+	filter["trending.score"] = bson.M{"$gt": 0}
+	return findVideos("GetTrendingVideos", req, sortOrders[entities.SortTrending], filter)
+}
@@ -0,0 +1,92 @@
+package get_video-search_video
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/pubsub"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Max number of videos replayed from the database per query
+const watchBatchSize = 100
+
+// Passes the videos matching the filter to send as they are inserted, in insertion order. When
+// afterId is set, the videos inserted after the video with that id are replayed from the database
+// first, so that clients can resume from the last video they received. Ids of stored videos are
+// generated on insert so they grow with insertion time. Returns when ctx is done or send fails,
+// ErrInvalidCursor when afterId is not a video id and pubsub.ErrSlowSubscriber when the caller
+// did not keep up with inserted videos, in which case it can resume from its last video.
+func WatchNewVideos(ctx context.Context, afterId string, videoFilter entities.VideoFilter, send func(entities.Video) error) error {
+	var lastId primitive.ObjectID
+	if afterId != "" {
+		var err error
+		if lastId, err = primitive.ObjectIDFromHex(afterId); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+
+	// Subscribes before replaying so that no video is missed in between
+	sub := pubsub.Subscribe()
+	defer sub.Close()
+
+	for !lastId.IsZero() {
+		videos, err := videosInsertedAfter(ctx, lastId, videoFilter)
+		if err != nil {
+			log.Errorf("WatchNewVideos: Error replaying videos: %v", err)
+			return err
+		}
+		for _, video := range videos {
+			if err := send(video); err != nil {
+				return err
+			}
+			lastId, _ = primitive.ObjectIDFromHex(video.Id)
+		}
+		if len(videos) < watchBatchSize {
+			break
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case video, ok := <-sub.Videos():
+			if !ok {
+				return sub.Err()
+			}
+			// Skips the videos which were already replayed. Hex ids compare in the order of the ids.
+			if video.Id <= lastId.Hex() {
+				continue
+			}
+			if !matchesFilter(video, videoFilter) {
+				continue
+			}
+			if err := send(video); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Returns the videos matching the filter inserted after the video with id lastId, in insertion order
+func videosInsertedAfter(ctx context.Context, lastId primitive.ObjectID, videoFilter entities.VideoFilter) ([]entities.Video, error) {
+	filter := filterQuery(videoFilter)
+	filter["_id"] = bson.M{"$gt": lastId}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(watchBatchSize).
+		SetProjection(bson.M{"titlePrefixes": 0})
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	videos := make([]entities.Video, 0)
+	err = cursor.All(ctx, &videos)
+	return videos, err
+}
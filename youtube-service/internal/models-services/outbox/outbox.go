@@ -0,0 +1,196 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/events"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of events published per query of the outbox
+	relayBatchSize = 100
+	// Published events older than this are deleted by mongo
+	publishedRetention = 7 * 24 * time.Hour
+)
+
+var collection *mongo.Collection
+
+// Event waiting in the outbox until it is accepted by the broker
+type entry struct {
+	Id primitive.ObjectID `bson:"_id"`
+	// Event encoded as published
+	Event       string     `bson:"event"`
+	Published   bool       `bson:"published"`
+	PublishedAt *time.Time `bson:"publishedAt,omitempty"`
+	// Number of failed publications and the error of the last one
+	FailedAttempts int64     `bson:"failedAttempts"`
+	LastError      string    `bson:"lastError,omitempty"`
+	CreatedAt      time.Time `bson:"createdAt"`
+}
+
+func SetCollection(client *mongo.Client) {
+	collection = client.Database("cmd").Collection("event_outbox")
+}
+
+// Creates the index of the events to publish, and the index expiring published events.
+// Events which are not published yet are never expired.
+func CreateIndexes() {
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "published", Value: 1}, {Key: "_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "publishedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(publishedRetention.Seconds())),
+		},
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := collection.Indexes().CreateMany(context.TODO(), models, options)
+	if err != nil {
+		log.Fatalf("CreateIndexes: Error creating event outbox indexes: %v", err)
+	}
+}
+
+// Set by Enable when a broker is configured
+var enabled bool
+
+// Makes the ingestion store the events of the videos it inserts in the outbox
+func Enable() {
+	enabled = true
+}
+
+// Returns whether the events of inserted videos are stored in the outbox
+func Enabled() bool {
+	return enabled
+}
+
+// Stores the events of the videos inserted by the ingestion, which Relay publishes. It is called in
+// the transaction inserting the videos, so that their events are stored if and only if they are.
+func Enqueue(ctx context.Context, videos []entities.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	entries := make([]interface{}, 0, len(videos))
+	for _, video := range videos {
+		id := primitive.NewObjectID()
+		event, err := events.NewVideoIngested(id.Hex(), video, now).Marshal()
+		if err != nil {
+			log.Errorf("Enqueue: Error encoding event of video %v: %v", video.UniqueId, err)
+			return err
+		}
+		entries = append(entries, entry{Id: id, Event: string(event), CreatedAt: now})
+	}
+
+	if _, err := collection.InsertMany(ctx, entries); err != nil {
+		log.Errorf("Enqueue: Error inserting events into the outbox: %v", err)
+		return err
+	}
+	log.Infof("Enqueue: Enqueued %v events", len(entries))
+	return nil
+}
+
+// Publishes the events of the outbox in the order they were enqueued until none is left.
+// Stops at the first event the broker does not accept so that it is retried first on the next run.
+// Events are marked published after the broker accepted them, so an event can be published twice
+// if marking it fails.
+func Relay(publisher events.Publisher) {
+	for {
+		published, err := relayBatch(publisher)
+		if err != nil || published < relayBatchSize {
+			return
+		}
+	}
+}
+
+// Returns the number of published events
+func relayBatch(publisher events.Publisher) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(relayBatchSize)
+	cursor, err := collection.Find(ctx, bson.M{"published": false}, findOptions)
+	if err != nil {
+		log.Errorf("Relay: Error fetching events of the outbox: %v", err)
+		return 0, err
+	}
+	entries := make([]entry, 0)
+	if err := cursor.All(ctx, &entries); err != nil {
+		log.Errorf("Relay: Error decoding events of the outbox: %v", err)
+		return 0, err
+	}
+
+	published, err := publishEntries(ctx, publisher, entries, mongoStore{})
+	if err != nil {
+		return published, err
+	}
+	if len(entries) > 0 {
+		log.Infof("Relay: Published %v events", len(entries))
+	}
+	return len(entries), nil
+}
+
+// Records the outcome of the publication of entries
+type store interface {
+	markPublished(ctx context.Context, id primitive.ObjectID) error
+	recordFailure(id primitive.ObjectID, err error)
+}
+
+// Publishes the entries in order. Entries which can't be decoded are skipped as they can't be
+// published on retries either. Stops at the first entry the broker does not accept, and returns the
+// number of entries handled before it.
+func publishEntries(ctx context.Context, publisher events.Publisher, entries []entry, s store) (int, error) {
+	for i, entry := range entries {
+		var event events.Event
+		if err := json.Unmarshal([]byte(entry.Event), &event); err != nil {
+			log.Errorf("Relay: Error decoding event %v: %v", entry.Id.Hex(), err)
+			s.markPublished(ctx, entry.Id)
+			continue
+		}
+		if err := publisher.Publish(ctx, event); err != nil {
+			log.Errorf("Relay: Error publishing event %v: %v", entry.Id.Hex(), err)
+			s.recordFailure(entry.Id, err)
+			return i, err
+		}
+		if err := s.markPublished(ctx, entry.Id); err != nil {
+			return i, err
+		}
+	}
+	return len(entries), nil
+}
+
+type mongoStore struct{}
+
+func (mongoStore) markPublished(ctx context.Context, id primitive.ObjectID) error {
+	return markPublished(ctx, id)
+}
+
+// The failure is recorded even when the context of the batch expired
+func (mongoStore) recordFailure(id primitive.ObjectID, err error) {
+	update := bson.M{
+		"$inc": bson.M{"failedAttempts": 1},
+		"$set": bson.M{"lastError": err.Error()},
+	}
+	if _, err := collection.UpdateByID(context.Background(), id, update); err != nil {
+		log.Errorf("Relay: Error recording failure of event %v: %v", id.Hex(), err)
+	}
+}
+
+func markPublished(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"published": true, "publishedAt": time.Now()}}
+	_, err := collection.UpdateByID(ctx, id, update)
+	if err != nil {
+		log.Errorf("Relay: Error marking event %v published: %v", id.Hex(), err)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/internal/events"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Publisher failing on the events of the given videos
+type fakePublisher struct {
+	failing   map[string]bool
+	published []string
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event events.Event) error {
+	if p.failing[event.Subject] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.Subject)
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+type fakeStore struct {
+	published []primitive.ObjectID
+	failed    []primitive.ObjectID
+}
+
+func (s *fakeStore) markPublished(ctx context.Context, id primitive.ObjectID) error {
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *fakeStore) recordFailure(id primitive.ObjectID, err error) {
+	s.failed = append(s.failed, id)
+}
+
+func newEntry(t *testing.T, uniqueId string) entry {
+	t.Helper()
+	id := primitive.NewObjectID()
+	event, err := events.NewVideoIngested(id.Hex(), entities.Video{UniqueId: uniqueId}, time.Now()).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned error %v", err)
+	}
+	return entry{Id: id, Event: string(event)}
+}
+
+func TestPublishEntries(t *testing.T) {
+	a, b, c := newEntry(t, "aaaaaaaaaaa"), newEntry(t, "bbbbbbbbbbb"), newEntry(t, "ccccccccccc")
+	undecodable := entry{Id: primitive.NewObjectID(), Event: "{"}
+
+	tests := []struct {
+		name      string
+		entries   []entry
+		failing   map[string]bool
+		handled   int
+		published []string
+		marked    []primitive.ObjectID
+		failed    []primitive.ObjectID
+	}{
+		{"all published", []entry{a, b, c}, nil, 3, []string{"aaaaaaaaaaa", "bbbbbbbbbbb", "ccccccccccc"}, []primitive.ObjectID{a.Id, b.Id, c.Id}, nil},
+		{"stops at the first failure", []entry{a, b, c}, map[string]bool{"bbbbbbbbbbb": true}, 1, []string{"aaaaaaaaaaa"}, []primitive.ObjectID{a.Id}, []primitive.ObjectID{b.Id}},
+		{"first event failing", []entry{a, b}, map[string]bool{"aaaaaaaaaaa": true}, 0, nil, nil, []primitive.ObjectID{a.Id}},
+		{"undecodable event skipped", []entry{a, undecodable, c}, nil, 3, []string{"aaaaaaaaaaa", "ccccccccccc"}, []primitive.ObjectID{a.Id, undecodable.Id, c.Id}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publisher := &fakePublisher{failing: test.failing}
+			s := &fakeStore{}
+			handled, err := publishEntries(context.Background(), publisher, test.entries, s)
+			if (err != nil) != (len(test.failed) > 0) {
+				t.Errorf("publishEntries returned error %v", err)
+			}
+			if handled != test.handled {
+				t.Errorf("publishEntries handled %d entries, want %d", handled, test.handled)
+			}
+			if !reflect.DeepEqual(publisher.published, test.published) {
+				t.Errorf("published %v, want %v", publisher.published, test.published)
+			}
+			if !reflect.DeepEqual(s.published, test.marked) {
+				t.Errorf("marked published %v, want %v", s.published, test.marked)
+			}
+			if !reflect.DeepEqual(s.failed, test.failed) {
+				t.Errorf("recorded failures of %v, want %v", s.failed, test.failed)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+	"github.com/youtube-service/models-services/get_video-search_video"
+	"github.com/youtube-service/pkg/searchquery"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Type of the events of ingested videos
+	eventVideoIngested = "video.ingested"
+	// Number of deliveries sent concurrently
+	deliveryWorkers = 4
+	// Time after which a delivery claimed by a worker which did not finish it is sent again
+	deliveryLease = time.Minute
+	// Delay before the first retry, doubled on every following retry up to maxRetryDelay
+	retryBaseDelay = 30 * time.Second
+	maxRetryDelay  = 6 * time.Hour
+	// Deliveries older than this are deleted by mongo
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Body of webhook requests
+type payload struct {
+	// Id of the delivery, the same on every attempt so that receivers can ignore duplicates
+	Id        string         `json:"id"`
+	Type      string         `json:"type"`
+	WebhookId string         `json:"webhookId"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Video     entities.Video `json:"video"`
+}
+
+// Creates the indexes of the deliveries to send and of the delivery log of each webhook, which also
+// expires old deliveries
+func CreateIndexes() {
+	models := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"status": entities.DeliveryPending}),
+		},
+		{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "_id", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+		},
+	}
+
+	options := options.CreateIndexes().SetMaxTime(10 * time.Second)
+
+	_, err := deliveriesCollection.Indexes().CreateMany(context.TODO(), models, options)
+	if err != nil {
+		log.Fatalf("CreateIndexes: Error creating webhook delivery indexes: %v", err)
+	}
+}
+
+// Reports whether the video passes the filters of the webhook
+func matches(webhook entities.Webhook, video entities.Video) bool {
+	if webhook.Topic != "" && video.SourceQuery != webhook.Topic {
+		return false
+	}
+	if webhook.ChannelId != "" && video.ChannelId != webhook.ChannelId {
+		return false
+	}
+	if webhook.Keyword != "" {
+		query, err := searchquery.Parse(webhook.Keyword)
+		if err != nil || !get_video-search_video.MatchesQuery(video, query) {
+			return false
+		}
+	}
+	return true
+}
+
+// Enqueues a delivery of each video to each webhook it matches. Called with the videos inserted
+// by the ingestion, the deliveries are sent by DeliverPending.
+func EnqueueDeliveries(videos []entities.Video) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	webhooks, err := findWebhooks(ctx)
+	if err != nil {
+		log.Errorf("EnqueueDeliveries: Error fetching webhooks: %v", err)
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]interface{}, 0)
+	for _, webhook := range webhooks {
+		for _, video := range videos {
+			if !matches(webhook, video) {
+				continue
+			}
+			id := primitive.NewObjectID()
+			body, err := json.Marshal(payload{
+				Id:        id.Hex(),
+				Type:      eventVideoIngested,
+				WebhookId: webhook.Id,
+				CreatedAt: now,
+				Video:     video,
+			})
+			if err != nil {
+				log.Errorf("EnqueueDeliveries: Error encoding payload: %v", err)
+				continue
+			}
+			deliveries = append(deliveries, bson.M{
+				"_id":            id,
+				"webhookId":      webhook.Id,
+				"videoId":        video.UniqueId,
+				"status":         entities.DeliveryPending,
+				"payload":        string(body),
+				"attempts":       bson.A{},
+				"failedAttempts": 0,
+				"nextAttemptAt":  now,
+				"createdAt":      now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	if _, err := deliveriesCollection.InsertMany(ctx, deliveries); err != nil {
+		log.Errorf("EnqueueDeliveries: Error inserting deliveries: %v", err)
+		return
+	}
+	log.Infof("EnqueueDeliveries: Enqueued %v webhook deliveries", len(deliveries))
+}
+
+// Sends the pending deliveries which are due until none is left. Failed deliveries are retried
+// with exponential backoff and dead lettered after WEBHOOK_MAX_ATTEMPTS attempts.
+func DeliverPending() {
+	var wg sync.WaitGroup
+	for i := 0; i < deliveryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				delivery, err := claimDelivery()
+				if errors.Is(err, mongo.ErrNoDocuments) {
+					return
+				}
+				if err != nil {
+					log.Errorf("DeliverPending: Error claiming delivery: %v", err)
+					return
+				}
+				deliver(delivery)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Leases the next due delivery so that other workers and instances don't send it concurrently
+func claimDelivery() (entities.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"status": entities.DeliveryPending, "nextAttemptAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(deliveryLease)}}
+	updateOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery entities.WebhookDelivery
+	err := deliveriesCollection.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&delivery)
+	return delivery, err
+}
+
+// Returns the HMAC-SHA256 signature of the timestamp and body, which receivers recompute with the
+// secret of the webhook to check that the request was sent by this service
+func sign(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sends the delivery and records the attempt
+func deliver(delivery entities.WebhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	webhook, err := findWebhook(ctx, delivery.WebhookId)
+	if errors.Is(err, ErrWebhookNotFound) {
+		// The webhook was deleted after the delivery was claimed
+		return
+	}
+	if err != nil {
+		log.Errorf("deliver: Error fetching webhook: %v", err)
+		return
+	}
+
+	attempt := entities.DeliveryAttempt{At: time.Now()}
+	attempt.StatusCode, err = send(webhook, delivery)
+	attempt.DurationMs = time.Since(attempt.At).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	set := bson.M{}
+	failedAttempts := delivery.FailedAttempts + 1
+	switch {
+	case err == nil:
+		set["status"] = entities.DeliverySucceeded
+	case failedAttempts >= configs.GetWebhookMaxAttempts():
+		set["status"] = entities.DeliveryDeadLettered
+		set["failedAttempts"] = failedAttempts
+		log.Errorf("deliver: Dead lettered delivery %v to webhook %v after %v attempts: %v", delivery.Id, webhook.Id, failedAttempts, err)
+	default:
+		set["failedAttempts"] = failedAttempts
+		set["nextAttemptAt"] = attempt.At.Add(retryDelay(failedAttempts))
+	}
+
+	objectId, _ := primitive.ObjectIDFromHex(delivery.Id)
+	update := bson.M{"$set": set, "$push": bson.M{"attempts": attempt}}
+	if _, err := deliveriesCollection.UpdateOne(ctx, bson.M{"_id": objectId}, update); err != nil {
+		log.Errorf("deliver: Error recording delivery attempt: %v", err)
+	}
+}
+
+// Returns the delay before retrying a delivery which failed the given number of times
+func retryDelay(failedAttempts int64) time.Duration {
+	if failedAttempts > 16 {
+		return maxRetryDelay
+	}
+	delay := retryBaseDelay << (failedAttempts - 1)
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// Posts the signed payload of the delivery to the webhook. Returns the status of the response, and
+// an error unless it is a 2xx.
+func send(webhook entities.Webhook, delivery entities.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "youtube-service-webhooks")
+	req.Header.Set("X-Webhook-Id", webhook.Id)
+	req.Header.Set("X-Webhook-Delivery", delivery.Id)
+	req.Header.Set("X-Webhook-Event", eventVideoIngested)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", sign(webhook.Secret, timestamp, delivery.Payload))
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// Returns the latest deliveries of the webhook, optionally only those with the given status
+func GetDeliveries(webhookId string, status string, limit int64) ([]entities.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := findWebhook(ctx, webhookId); err != nil {
+		if !errors.Is(err, ErrWebhookNotFound) {
+			log.Errorf("GetDeliveries: Error fetching webhook: %v", err)
+		}
+		return nil, err
+	}
+
+	filter := bson.M{"webhookId": webhookId}
+	if status != "" {
+		filter["status"] = status
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"payload": 0})
+	cursor, err := deliveriesCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		log.Errorf("GetDeliveries: Error fetching deliveries: %v", err)
+		return nil, err
+	}
+	deliveries := make([]entities.WebhookDelivery, 0)
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		log.Errorf("GetDeliveries: Error decoding deliveries: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Sends a delivery which succeeded or was dead lettered again, e.g. once the receiver was fixed.
+// Its attempts are kept and it is retried up to WEBHOOK_MAX_ATTEMPTS times again. Returns
+// ErrDeliveryPending if the delivery is still being sent.
+func Redeliver(webhookId string, deliveryId string) (entities.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var delivery entities.WebhookDelivery
+	objectId, err := primitive.ObjectIDFromHex(deliveryId)
+	if err != nil {
+		return delivery, ErrDeliveryNotFound
+	}
+	filter := bson.M{
+		"_id":       objectId,
+		"webhookId": webhookId,
+		"status":    bson.M{"$ne": entities.DeliveryPending},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":         entities.DeliveryPending,
+		"failedAttempts": 0,
+		"nextAttemptAt":  time.Now(),
+	}}
+	updateOptions := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"payload": 0})
+	err = deliveriesCollection.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		count, countErr := deliveriesCollection.CountDocuments(ctx, bson.M{"_id": objectId, "webhookId": webhookId})
+		if countErr == nil && count > 0 {
+			return delivery, ErrDeliveryPending
+		}
+		return delivery, ErrDeliveryNotFound
+	}
+	if err != nil {
+		log.Errorf("Redeliver: Error updating delivery: %v", err)
+	}
+	return delivery, err
+}
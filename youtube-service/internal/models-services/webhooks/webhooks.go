@@ -0,0 +1,186 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/youtube-service/internal/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	collection           *mongo.Collection
+	deliveriesCollection *mongo.Collection
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrDeliveryPending  = errors.New("delivery is pending")
+)
+
+// Changes of a webhook. Nil fields are left unchanged.
+type WebhookUpdate struct {
+	Url       *string
+	Secret    *string
+	Topic     *string
+	ChannelId *string
+	Keyword   *string
+}
+
+func SetCollection(client *mongo.Client) {
+	collection = client.Database("cmd").Collection("webhooks")
+	deliveriesCollection = client.Database("cmd").Collection("webhook_deliveries")
+}
+
+// Returns a random secret to sign the payloads of a webhook created without one
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Inserts the webhook and returns it along with its secret, which is generated if empty
+func CreateWebhook(webhook entities.Webhook) (entities.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if webhook.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			log.Errorf("CreateWebhook: Error generating secret: %v", err)
+			return webhook, err
+		}
+		webhook.Secret = secret
+	}
+	webhook.Id = ""
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	result, err := collection.InsertOne(ctx, webhook)
+	if err != nil {
+		log.Errorf("CreateWebhook: Error inserting webhook: %v", err)
+		return webhook, err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		webhook.Id = id.Hex()
+	}
+	log.Infof("CreateWebhook: Created webhook %v", webhook.Id)
+	return webhook, nil
+}
+
+// Returns all the webhooks, most recently created first
+func GetWebhooks() ([]entities.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	webhooks, err := findWebhooks(ctx)
+	if err != nil {
+		log.Errorf("GetWebhooks: Error fetching webhooks: %v", err)
+	}
+	return webhooks, err
+}
+
+func findWebhooks(ctx context.Context) ([]entities.Webhook, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := make([]entities.Webhook, 0)
+	err = cursor.All(ctx, &webhooks)
+	return webhooks, err
+}
+
+func GetWebhook(id string) (entities.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	webhook, err := findWebhook(ctx, id)
+	if err != nil && !errors.Is(err, ErrWebhookNotFound) {
+		log.Errorf("GetWebhook: Error fetching webhook: %v", err)
+	}
+	return webhook, err
+}
+
+func findWebhook(ctx context.Context, id string) (entities.Webhook, error) {
+	var webhook entities.Webhook
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return webhook, ErrWebhookNotFound
+	}
+	err = collection.FindOne(ctx, bson.M{"_id": objectId}).Decode(&webhook)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return webhook, ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+// Applies the changes to the webhook with the given id and returns it. Deliveries already
+// enqueued are still sent to the previous URL.
+func UpdateWebhook(id string, update WebhookUpdate) (entities.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var webhook entities.Webhook
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return webhook, ErrWebhookNotFound
+	}
+	set := bson.M{"updatedAt": time.Now()}
+	for field, value := range map[string]*string{
+		"url":       update.Url,
+		"secret":    update.Secret,
+		"topic":     update.Topic,
+		"channelId": update.ChannelId,
+		"keyword":   update.Keyword,
+	} {
+		if value != nil {
+			set[field] = *value
+		}
+	}
+
+	updateOptions := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = collection.FindOneAndUpdate(ctx, bson.M{"_id": objectId}, bson.M{"$set": set}, updateOptions).Decode(&webhook)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return webhook, ErrWebhookNotFound
+	}
+	if err != nil {
+		log.Errorf("UpdateWebhook: Error updating webhook: %v", err)
+	}
+	return webhook, err
+}
+
+// Deletes the webhook with the given id along with its deliveries
+func DeleteWebhook(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		log.Errorf("DeleteWebhook: Error deleting webhook: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrWebhookNotFound
+	}
+	if _, err := deliveriesCollection.DeleteMany(ctx, bson.M{"webhookId": id}); err != nil {
+		log.Errorf("DeleteWebhook: Error deleting deliveries: %v", err)
+	}
+	log.Infof("DeleteWebhook: Deleted webhook %v", id)
+	return nil
+}
@@ -0,0 +1,192 @@
+package openapi
+
+import (
+	"embed"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/entities"
+)
+
+// Document is the OpenAPI 3 document of the service
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Operation struct {
+	OperationId string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Swagger UI page rendering the document served next to it
+//
+//go:embed swagger.html
+var SwaggerUI []byte
+
+// Assets of the Swagger UI page, served by the service so that the page works offline and under a
+// Content-Security-Policy allowing only its own scripts. swagger-ui.css and swagger-ui-bundle.js are
+// vendored from the swagger-ui-dist version in swagger-ui/VERSION with make swagger-ui.
+//
+//go:embed swagger-ui
+var swaggerUIAssets embed.FS
+
+// Returns the content of the Swagger UI asset with the given name, and false if there is no such asset
+func SwaggerUIAsset(name string) ([]byte, bool) {
+	if strings.Contains(name, "/") {
+		return nil, false
+	}
+	content, err := swaggerUIAssets.ReadFile("swagger-ui/" + name)
+	return content, err == nil
+}
+
+var (
+	document     Document
+	documentOnce sync.Once
+)
+
+// Returns the OpenAPI document of the routes created by routers.SetRoutes.
+// Built on first use, after the configs it documents limits of have been initialized.
+func GetDocument() Document {
+	documentOnce.Do(func() {
+		document = newDocument()
+	})
+	return document
+}
+
+var fiberParamRegex = regexp.MustCompile(`/:(\w+)`)
+
+// Converts a fiber route path to an OpenAPI path, e.g. /videos/:uniqueId to /videos/{uniqueId}
+func openapiPath(fiberPath string) string {
+	path := fiberParamRegex.ReplaceAllString(fiberPath, "/{$1}")
+	return strings.ReplaceAll(path, "\\:", ":")
+}
+
+// Returns the method and path of the routes of the app which are missing from the document,
+// so that the tests of the routers fail when a route is added without documenting it.
+// Middlewares mounted on the root and the HEAD routes fiber adds for GET routes are ignored.
+func Undocumented(app *fiber.App) []string {
+	paths := GetDocument().Paths
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, routes := range app.Stack() {
+		for _, route := range routes {
+			if route.Method == fiber.MethodHead || route.Path == "/" {
+				continue
+			}
+			path := openapiPath(route.Path)
+			key := route.Method + " " + path
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := paths[path][strings.ToLower(route.Method)]; !ok {
+				missing = append(missing, key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func newDocument() Document {
+	schemas := make(map[string]*Schema)
+	schemaOf(reflect.TypeOf(entities.Video{}), schemas)
+	schemaOf(reflect.TypeOf(entities.FacetCount{}), schemas)
+	schemaOf(reflect.TypeOf(entities.ApiKey{}), schemas)
+	schemaOf(reflect.TypeOf(entities.Webhook{}), schemas)
+	schemaOf(reflect.TypeOf(entities.WebhookDelivery{}), schemas)
+	schemaOf(reflect.TypeOf(entities.AlertRule{}), schemas)
+	schemas["CreatedWebhook"] = withSecret(schemas["Webhook"])
+	for name, schema := range extraSchemas() {
+		schemas[name] = schema
+	}
+
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Youtube Service",
+			Description: "Fetches the latest YouTube videos of a query and serves, searches and ranks them.",
+			Version:     "1",
+		},
+		Paths:      make(map[string]map[string]Operation),
+		Components: Components{Schemas: schemas},
+	}
+
+	ops := operations()
+	for key, op := range ops {
+		addOperation(doc.Paths, key, op)
+	}
+	for alias, successor := range deprecatedAliases {
+		op := ops[successor]
+		op.OperationId += "Deprecated"
+		op.Deprecated = true
+		op.Description = "Deprecated alias of `" + successor + "`. Responses carry a Deprecation header and a Link header to the successor."
+		addOperation(doc.Paths, alias, op)
+	}
+	return doc
+}
+
+// Adds the operation under its "METHOD /path" key
+func addOperation(paths map[string]map[string]Operation, key string, op Operation) {
+	method, path, _ := strings.Cut(key, " ")
+	if paths[path] == nil {
+		paths[path] = make(map[string]Operation)
+	}
+	paths[path][strings.ToLower(method)] = op
+}
+
+// Returns the schema of a webhook as returned when it is created, the only time its secret is
+func withSecret(webhook *Schema) *Schema {
+	properties := map[string]*Schema{
+		"secret": {Type: "string", Description: "Key of the HMAC-SHA256 signature of the deliveries"},
+	}
+	for name, property := range webhook.Properties {
+		properties[name] = property
+	}
+	return object(properties, append([]string{"secret"}, webhook.Required...)...)
+}
@@ -0,0 +1,569 @@
+package openapi
+
+import (
+	"fmt"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/configs"
+	"github.com/youtube-service/internal/entities"
+)
+
+const (
+	tagVideos   = "videos"
+	tagKeys     = "keys"
+	tagWebhooks = "webhooks"
+	tagAlerts   = "alerts"
+	tagFeeds    = "feeds"
+	tagGraphQL  = "graphql"
+	tagDocs     = "docs"
+)
+
+// Paths from before the API was versioned, by their /v1 successor
+var deprecatedAliases = map[string]string{
+	"GET /get_video":                 "GET /v1/videos",
+	"GET /search_video":              "GET /v1/videos/search",
+	"GET /trending":                  "GET /v1/videos/trending",
+	"GET /videos/{uniqueId}":         "GET /v1/videos/{uniqueId}",
+	"POST /videos:batchGet":          "POST /v1/videos:batchGet",
+	"GET /videos/{uniqueId}/related": "GET /v1/videos/{uniqueId}/related",
+	"GET /suggest":                   "GET /v1/suggestions",
+	"POST /add_key":                  "POST /v1/keys",
+}
+
+func number(n float64) *float64 {
+	return &n
+}
+
+func stringSchema(enum ...string) *Schema {
+	return &Schema{Type: "string", Enum: enum}
+}
+
+func integerSchema(min, max float64) *Schema {
+	return &Schema{Type: "integer", Minimum: number(min), Maximum: number(max)}
+}
+
+func queryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: schema}}
+}
+
+func jsonResponse(description string, schema *Schema) Response {
+	return Response{Description: description, Content: jsonContent(schema)}
+}
+
+func errorResponse(description string) Response {
+	return jsonResponse(description, ref("Error"))
+}
+
+var uniqueIdParam = Parameter{Name: "uniqueId", In: "path", Description: "YouTube id of the video", Required: true, Schema: stringSchema()}
+
+var webhookIdParam = Parameter{Name: "webhookId", In: "path", Description: "Id of the webhook", Required: true, Schema: stringSchema()}
+
+var ruleIdParam = Parameter{Name: "ruleId", In: "path", Description: "Id of the alert rule", Required: true, Schema: stringSchema()}
+
+var adminParam = Parameter{Name: fiber.HeaderAuthorization, In: "header", Description: "Bearer ADMIN_TOKEN", Required: true, Schema: stringSchema()}
+
+// Operation of the feed of the topic in the given format
+func feedOperation(operationId, format, contentType string) Operation {
+	return Operation{
+		OperationId: operationId,
+		Summary:     "Newest stored videos fetched for the topic as " + format,
+		Tags:        []string{tagFeeds},
+		Parameters: []Parameter{
+			{Name: "topic", In: "path", Description: "Query the videos were fetched for", Required: true, Schema: stringSchema()},
+			{Name: fiber.HeaderIfNoneMatch, In: "header", Description: "ETag of a previous response", Schema: stringSchema()},
+			{Name: fiber.HeaderIfModifiedSince, In: "header", Description: "Last-Modified of a previous response", Schema: stringSchema()},
+		},
+		Responses: map[string]Response{
+			"200": {Description: format, Content: map[string]MediaType{contentType: {Schema: &Schema{Type: "string"}}}},
+			"304": {Description: "The feed did not change since the previous response"},
+			"400": errorResponse("Invalid request"),
+			"500": errorResponse("Internal error"),
+		},
+	}
+}
+
+// Body of the requests creating and updating webhooks
+func webhookInput(required ...string) *Schema {
+	return object(map[string]*Schema{
+		"url":        {Type: "string", Description: "http or https URL the deliveries are posted to"},
+		"secret":     {Type: "string", Description: "Key of the HMAC signature, 16 to 256 characters, generated when empty"},
+		"topic":      {Type: "string", Description: "Only videos fetched for this query, empty for all"},
+		"channel_id": {Type: "string", Description: "Only videos of this channel, empty for all"},
+		"keyword":    {Type: "string", Description: "Only videos matching this search query, empty for all"},
+	}, required...)
+}
+
+// Responses of the endpoints which require the admin token
+func adminResponses(responses map[string]Response) map[string]Response {
+	responses["401"] = errorResponse("The admin token is missing or invalid")
+	responses["403"] = errorResponse("No admin token is configured")
+	return responses
+}
+
+// Query params of paginated endpoints accepting the given sort orders
+func pageParams(sorts ...string) []Parameter {
+	return []Parameter{
+		queryParam("page", "Page number, ignored when cursor is set", &Schema{Type: "integer", Minimum: number(1)}),
+		queryParam("per_page", "Number of videos per page", integerSchema(1, float64(configs.GetMaxPerPageLimit()))),
+		queryParam("cursor", "next_cursor of the previous page", stringSchema()),
+		queryParam("sort", "Sort order", stringSchema(sorts...)),
+	}
+}
+
+// Query params of the video filters
+func filterParams() []Parameter {
+	return []Parameter{
+		queryParam("published_after", "Only videos published at or after this RFC 3339 timestamp", &Schema{Type: "string", Format: "date-time"}),
+		queryParam("published_before", "Only videos published before this RFC 3339 timestamp", &Schema{Type: "string", Format: "date-time"}),
+		queryParam("duration", "Only videos of this duration bucket", stringSchema(entities.DurationShort, entities.DurationMedium, entities.DurationLong)),
+		queryParam("live", "Only videos with this live broadcast content", stringSchema("none", "live", "upcoming")),
+		queryParam("min_views", "Only videos with at least this many views", &Schema{Type: "integer", Format: "int64", Minimum: number(0)}),
+		queryParam("channel_id", "Only videos of this channel", stringSchema()),
+		queryParam("topic", "Only videos fetched for this query", stringSchema()),
+		queryParam("language", "Only videos in this language", stringSchema()),
+	}
+}
+
+func concat(params ...[]Parameter) []Parameter {
+	all := make([]Parameter, 0)
+	for _, p := range params {
+		all = append(all, p...)
+	}
+	return all
+}
+
+// Schemas of the response bodies which are not entities
+func extraSchemas() map[string]*Schema {
+	videos := array(ref("Video"))
+	return map[string]*Schema{
+		"Error": object(map[string]*Schema{
+			"error": object(map[string]*Schema{
+				"code":       {Type: "string", Description: "Derived from the HTTP status, e.g. invalid_argument or not_found"},
+				"message":    {Type: "string"},
+				"request_id": {Type: "string", Description: "Value of the X-Request-ID response header"},
+			}, "code", "message", "request_id"),
+		}, "error"),
+		"PageLinks": object(map[string]*Schema{
+			"self": {Type: "string"},
+			"next": {Type: "string"},
+			"prev": {Type: "string"},
+		}, "self"),
+		"VideoPage": object(map[string]*Schema{
+			"videos":      videos,
+			"page":        {Type: "integer", Description: "Only set for page number based requests"},
+			"per_page":    {Type: "integer"},
+			"total":       {Type: "integer", Format: "int64"},
+			"has_more":    {Type: "boolean"},
+			"next_cursor": {Type: "string"},
+			"links":       ref("PageLinks"),
+		}, "videos", "per_page", "total", "has_more", "next_cursor", "links"),
+		"SearchPage": object(map[string]*Schema{
+			"videos":          videos,
+			"page":            {Type: "integer", Description: "Only set for page number based requests"},
+			"per_page":        {Type: "integer"},
+			"total":           {Type: "integer", Format: "int64"},
+			"has_more":        {Type: "boolean"},
+			"next_cursor":     {Type: "string"},
+			"links":           ref("PageLinks"),
+			"did_you_mean":    array(&Schema{Type: "string"}),
+			"corrected_query": {Type: "string", Description: "Set when the results are those of the closest correction of the query"},
+			"facets":          {Type: "object", AdditionalProperties: array(ref("FacetCount"))},
+		}, "videos", "per_page", "total", "has_more", "next_cursor", "links"),
+	}
+}
+
+// Operations of the /v1 routes by "METHOD /path"
+func operations() map[string]Operation {
+	badRequest := errorResponse("Invalid request")
+	internal := errorResponse("Internal error")
+	notFound := errorResponse("Video not found")
+	webhookNotFound := errorResponse("Webhook not found")
+	ruleNotFound := errorResponse("Alert rule not found")
+	graphqlResult := jsonResponse("Result of the operation, with the errors of the fields which failed", object(map[string]*Schema{
+		"data":   {Type: "object"},
+		"errors": array(&Schema{Type: "object"}),
+	}))
+	maxIds := configs.GetMaxBatchGetIds()
+
+	return map[string]Operation{
+		"GET /v1/videos": {
+			OperationId: "listVideos",
+			Summary:     "List stored videos, latest published first by default",
+			Tags:        []string{tagVideos},
+			Parameters:  concat(pageParams(entities.SortNewest, entities.SortOldest, entities.SortViews, entities.SortLikes), filterParams()),
+			Responses: map[string]Response{
+				"200": jsonResponse("A page of videos", ref("VideoPage")),
+				"400": badRequest,
+				"500": internal,
+			},
+		},
+		"GET /v1/videos/search": {
+			OperationId: "searchVideos",
+			Summary:     "Search stored videos by title, description and tags",
+			Tags:        []string{tagVideos},
+			Parameters: concat([]Parameter{
+				{Name: "query", In: "query", Description: "Search query, supporting quoted phrases, -negation, OR and title:, description: and channel: fields", Required: true, Schema: stringSchema()},
+				queryParam("lang", "Language code of the query, or none to search without stemming", stringSchema()),
+				queryParam("recency_half_life_hours", "Age at which the score of a video halves, 0 disables the recency decay", &Schema{Type: "number", Minimum: number(0)}),
+				queryParam("debug", "Adds a scoreBreakdown to each video", &Schema{Type: "boolean"}),
+				queryParam("facets", "Comma separated list of facets to count", stringSchema()),
+				queryParam("highlight_pre", "Marker inserted before matched terms", stringSchema()),
+				queryParam("highlight_post", "Marker inserted after matched terms", stringSchema()),
+			}, pageParams(entities.SortRelevance, entities.SortNewest, entities.SortOldest, entities.SortViews, entities.SortLikes), filterParams()),
+			Responses: map[string]Response{
+				"200": jsonResponse("A page of matching videos", ref("SearchPage")),
+				"400": badRequest,
+				"500": internal,
+			},
+		},
+		"GET /v1/videos/trending": {
+			OperationId: "listTrendingVideos",
+			Summary:     "List the videos whose views and likes grew fastest over the trending window",
+			Tags:        []string{tagVideos},
+			Parameters:  concat(pageParams(entities.SortTrending), filterParams()),
+			Responses: map[string]Response{
+				"200": jsonResponse("A page of trending videos", ref("VideoPage")),
+				"400": badRequest,
+				"500": internal,
+			},
+		},
+		"GET /v1/videos/stream": {
+			OperationId: "streamVideos",
+			Summary:     "Stream the videos inserted from now on as server-sent events",
+			Description: "Each event has type video, the id of the video as id and the video as JSON data. " +
+				"Streams resume after the video of the Last-Event-ID header, which EventSource sends when it reconnects, or of after_id.",
+			Tags: []string{tagVideos},
+			Parameters: []Parameter{
+				queryParam("query", "Only videos matching this search query, at most 500 characters", stringSchema()),
+				queryParam("topic", "Only videos fetched for this search query", stringSchema()),
+				queryParam("channel_id", "Only videos of this YouTube channel", stringSchema()),
+				queryParam("after_id", "Replays the videos inserted after the video with this id first", stringSchema()),
+				{Name: "Last-Event-ID", In: "header", Description: "Takes precedence over after_id", Schema: stringSchema()},
+			},
+			Responses: map[string]Response{
+				"200": {Description: "Stream of server-sent events", Content: map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}}},
+				"400": badRequest,
+			},
+		},
+		"POST /v1/videos:batchGet": {
+			OperationId: "batchGetVideos",
+			Summary:     "Get the stored videos with the given YouTube ids",
+			Tags:        []string{tagVideos},
+			RequestBody: &RequestBody{
+				Required: true,
+				Content: jsonContent(object(map[string]*Schema{
+					"ids": {Type: "array", Items: &Schema{Type: "string"}, Description: fmt.Sprintf("Between 1 and %d YouTube ids", maxIds)},
+				}, "ids")),
+			},
+			Responses: map[string]Response{
+				"200": jsonResponse("The stored videos in the order of the ids, and the ids which aren't stored", object(map[string]*Schema{
+					"videos":    array(ref("Video")),
+					"not_found": array(&Schema{Type: "string"}),
+				}, "videos", "not_found")),
+				"400": badRequest,
+				"500": internal,
+			},
+		},
+		"GET /v1/videos/{uniqueId}": {
+			OperationId: "getVideo",
+			Summary:     "Get the stored video with the given YouTube id",
+			Tags:        []string{tagVideos},
+			Parameters:  []Parameter{uniqueIdParam},
+			Responses: map[string]Response{
+				"200": jsonResponse("The video", ref("Video")),
+				"404": notFound,
+				"500": internal,
+			},
+		},
+		"GET /v1/videos/{uniqueId}/related": {
+			OperationId: "listRelatedVideos",
+			Summary:     "List the stored videos most similar to the given video",
+			Tags:        []string{tagVideos},
+			Parameters: []Parameter{
+				uniqueIdParam,
+				queryParam("limit", "Number of videos returned, 10 by default", integerSchema(1, 50)),
+			},
+			Responses: map[string]Response{
+				"200": jsonResponse("Related videos, most similar first", object(map[string]*Schema{
+					"videos": array(ref("Video")),
+				}, "videos")),
+				"400": badRequest,
+				"404": notFound,
+				"500": internal,
+			},
+		},
+		"GET /v1/suggestions": {
+			OperationId: "listSuggestions",
+			Summary:     "Complete a prefix with video titles and popular search queries",
+			Tags:        []string{tagVideos},
+			Parameters: []Parameter{
+				{Name: "prefix", In: "query", Description: "Prefix typed by the user, at most 100 characters", Required: true, Schema: stringSchema()},
+				queryParam("limit", "Max number of titles and of queries, 5 by default", integerSchema(1, 20)),
+			},
+			Responses: map[string]Response{
+				"200": jsonResponse("Suggestions", object(map[string]*Schema{
+					"titles":  array(&Schema{Type: "string"}),
+					"queries": array(&Schema{Type: "string"}),
+				}, "titles", "queries")),
+				"400": badRequest,
+				"500": internal,
+			},
+		},
+		"GET /v1/feeds/{topic}.atom": feedOperation("getAtomFeed", "an Atom feed", "application/atom+xml"),
+		"GET /v1/feeds/{topic}.rss":  feedOperation("getRssFeed", "an RSS 2.0 feed", "application/rss+xml"),
+		"GET /v1/feeds/{topic}.json": feedOperation("getJsonFeed", "a JSON Feed 1.1 document", "application/feed+json"),
+		// Same feeds without the version, kept stable for feed readers
+		"GET /feeds/{topic}.atom": feedOperation("getUnversionedAtomFeed", "an Atom feed", "application/atom+xml"),
+		"GET /feeds/{topic}.rss":  feedOperation("getUnversionedRssFeed", "an RSS 2.0 feed", "application/rss+xml"),
+		"GET /feeds/{topic}.json": feedOperation("getUnversionedJsonFeed", "a JSON Feed 1.1 document", "application/feed+json"),
+		"POST /v1/keys": {
+			OperationId: "addApiKey",
+			Summary:     "Add a YouTube Data API key used once the quota of the current one is exhausted",
+			Tags:        []string{tagKeys},
+			Parameters: []Parameter{
+				{Name: "key", In: "query", Description: "YouTube Data API key", Required: true, Schema: stringSchema()},
+			},
+			Responses: map[string]Response{
+				"200": jsonResponse("The key was added", object(map[string]*Schema{
+					"message": {Type: "string"},
+				}, "message")),
+				"400": badRequest,
+				"401": errorResponse("The key is not a valid YouTube Data API key"),
+				"500": internal,
+			},
+		},
+		"POST /v1/webhooks": {
+			OperationId: "createWebhook",
+			Summary:     "Subscribe a URL to the ingested videos matching the filters of the webhook",
+			Tags:        []string{tagWebhooks},
+			Parameters:  []Parameter{adminParam},
+			RequestBody: &RequestBody{Required: true, Content: jsonContent(webhookInput("url"))},
+			Responses: adminResponses(map[string]Response{
+				"201": jsonResponse("The webhook, along with its secret", ref("CreatedWebhook")),
+				"400": badRequest,
+				"500": internal,
+			}),
+		},
+		"GET /v1/webhooks": {
+			OperationId: "listWebhooks",
+			Summary:     "List the webhooks, newest first",
+			Tags:        []string{tagWebhooks},
+			Parameters:  []Parameter{adminParam},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The webhooks", object(map[string]*Schema{
+					"webhooks": array(ref("Webhook")),
+				}, "webhooks")),
+				"500": internal,
+			}),
+		},
+		"GET /v1/webhooks/{webhookId}": {
+			OperationId: "getWebhook",
+			Summary:     "Get the webhook with the given id",
+			Tags:        []string{tagWebhooks},
+			Parameters:  []Parameter{adminParam, webhookIdParam},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The webhook", ref("Webhook")),
+				"400": badRequest,
+				"404": webhookNotFound,
+				"500": internal,
+			}),
+		},
+		"PATCH /v1/webhooks/{webhookId}": {
+			OperationId: "updateWebhook",
+			Summary:     "Change the URL, secret or filters of the webhook, leaving the absent properties unchanged",
+			Tags:        []string{tagWebhooks},
+			Parameters:  []Parameter{adminParam, webhookIdParam},
+			RequestBody: &RequestBody{Required: true, Content: jsonContent(webhookInput())},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The updated webhook", ref("Webhook")),
+				"400": badRequest,
+				"404": webhookNotFound,
+				"500": internal,
+			}),
+		},
+		"DELETE /v1/webhooks/{webhookId}": {
+			OperationId: "deleteWebhook",
+			Summary:     "Delete the webhook and its deliveries",
+			Tags:        []string{tagWebhooks},
+			Parameters:  []Parameter{adminParam, webhookIdParam},
+			Responses: adminResponses(map[string]Response{
+				"204": {Description: "The webhook was deleted"},
+				"400": badRequest,
+				"404": webhookNotFound,
+				"500": internal,
+			}),
+		},
+		"GET /v1/webhooks/{webhookId}/deliveries": {
+			OperationId: "listWebhookDeliveries",
+			Summary:     "List the latest deliveries of the webhook with their attempts, newest first",
+			Tags:        []string{tagWebhooks},
+			Parameters: []Parameter{
+				adminParam,
+				webhookIdParam,
+				queryParam("status", "Only deliveries with this status", stringSchema(entities.DeliveryPending, entities.DeliverySucceeded, entities.DeliveryDeadLettered)),
+				queryParam("limit", "Number of deliveries returned, 20 by default", integerSchema(1, 100)),
+			},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The deliveries", object(map[string]*Schema{
+					"deliveries": array(ref("WebhookDelivery")),
+				}, "deliveries")),
+				"400": badRequest,
+				"404": webhookNotFound,
+				"500": internal,
+			}),
+		},
+		"POST /v1/webhooks/{webhookId}/deliveries/{deliveryId}:redeliver": {
+			OperationId: "redeliverWebhookDelivery",
+			Summary:     "Send a delivery which succeeded or was dead lettered again",
+			Tags:        []string{tagWebhooks},
+			Parameters: []Parameter{
+				adminParam,
+				webhookIdParam,
+				{Name: "deliveryId", In: "path", Description: "Id of the delivery", Required: true, Schema: stringSchema()},
+			},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The delivery, pending again", ref("WebhookDelivery")),
+				"400": badRequest,
+				"404": errorResponse("Webhook or delivery not found"),
+				"409": errorResponse("The delivery is already pending"),
+				"500": internal,
+			}),
+		},
+		"POST /v1/alerts": {
+			OperationId: "createAlertRule",
+			Summary:     "Create a rule alerting through Slack or email on the ingested videos matching its watch phrase",
+			Tags:        []string{tagAlerts},
+			Parameters:  []Parameter{adminParam},
+			RequestBody: &RequestBody{Required: true, Content: jsonContent(object(map[string]*Schema{
+				"name":       {Type: "string"},
+				"query":      {Type: "string", Description: "Watch phrase, with the syntax of search queries"},
+				"topic":      {Type: "string", Description: "Only videos fetched for this query, empty for all"},
+				"channel_id": {Type: "string", Description: "Only videos of this channel, empty for all"},
+				"notifier":   stringSchema(entities.NotifierSlack, entities.NotifierEmail),
+				"slack_url":  {Type: "string", Description: "Slack compatible incoming webhook, required by the slack notifier"},
+				"recipients": {Type: "array", Items: &Schema{Type: "string", Format: "email"}, Description: "Up to 20 addresses, required by the email notifier"},
+			}, "name", "query", "notifier"))},
+			Responses: adminResponses(map[string]Response{
+				"201": jsonResponse("The alert rule", ref("AlertRule")),
+				"400": badRequest,
+				"500": internal,
+			}),
+		},
+		"GET /v1/alerts": {
+			OperationId: "listAlertRules",
+			Summary:     "List the alert rules, newest first",
+			Tags:        []string{tagAlerts},
+			Parameters:  []Parameter{adminParam},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The alert rules", object(map[string]*Schema{
+					"rules": array(ref("AlertRule")),
+				}, "rules")),
+				"500": internal,
+			}),
+		},
+		"GET /v1/alerts/{ruleId}": {
+			OperationId: "getAlertRule",
+			Summary:     "Get the alert rule with the given id",
+			Tags:        []string{tagAlerts},
+			Parameters:  []Parameter{adminParam, ruleIdParam},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The alert rule", ref("AlertRule")),
+				"400": badRequest,
+				"404": ruleNotFound,
+				"500": internal,
+			}),
+		},
+		"DELETE /v1/alerts/{ruleId}": {
+			OperationId: "deleteAlertRule",
+			Summary:     "Delete the alert rule",
+			Tags:        []string{tagAlerts},
+			Parameters:  []Parameter{adminParam, ruleIdParam},
+			Responses: adminResponses(map[string]Response{
+				"204": {Description: "The alert rule was deleted"},
+				"400": badRequest,
+				"404": ruleNotFound,
+				"500": internal,
+			}),
+		},
+		"POST /v1/alerts/{ruleId}:test": {
+			OperationId: "testAlertRule",
+			Summary:     "Send a test alert with the newest stored video matching the rule through its notifier",
+			Tags:        []string{tagAlerts},
+			Parameters:  []Parameter{adminParam, ruleIdParam},
+			Responses: adminResponses(map[string]Response{
+				"200": jsonResponse("The test alert was sent", object(map[string]*Schema{
+					"message": {Type: "string"},
+				}, "message")),
+				"400": badRequest,
+				"404": ruleNotFound,
+				"500": internal,
+				"502": errorResponse("The notifier failed to send the alert"),
+			}),
+		},
+		"GET /v1/graphql": {
+			OperationId: "queryGraphql",
+			Summary:     "Execute a GraphQL query over videos, channels, ingestion runs and API keys",
+			Tags:        []string{tagGraphQL},
+			Parameters: []Parameter{
+				{Name: "query", In: "query", Description: "GraphQL document", Required: true, Schema: stringSchema()},
+				queryParam("operationName", "Operation to execute when the document has several", stringSchema()),
+				queryParam("variables", "JSON object of the variables of the operation", stringSchema()),
+				{Name: fiber.HeaderAuthorization, In: "header", Description: "Bearer ADMIN_TOKEN, required for API key management", Schema: stringSchema()},
+			},
+			Responses: map[string]Response{
+				"200": graphqlResult,
+				"400": badRequest,
+				"405": errorResponse("The operation is a mutation, which must be sent in a POST request"),
+			},
+		},
+		"POST /v1/graphql": {
+			OperationId: "executeGraphql",
+			Summary:     "Execute a GraphQL query or mutation over videos, channels, ingestion runs and API keys",
+			Tags:        []string{tagGraphQL},
+			Parameters: []Parameter{
+				{Name: fiber.HeaderAuthorization, In: "header", Description: "Bearer ADMIN_TOKEN, required for API key management", Schema: stringSchema()},
+			},
+			RequestBody: &RequestBody{Required: true, Content: jsonContent(object(map[string]*Schema{
+				"query":         {Type: "string"},
+				"operationName": {Type: "string"},
+				"variables":     {Type: "object"},
+			}, "query"))},
+			Responses: map[string]Response{
+				"200": graphqlResult,
+				"400": badRequest,
+			},
+		},
+		"GET /v1/openapi.json": {
+			OperationId: "getOpenApiDocument",
+			Summary:     "This OpenAPI document",
+			Tags:        []string{tagDocs},
+			Responses: map[string]Response{
+				"200": jsonResponse("OpenAPI 3 document", &Schema{Type: "object"}),
+			},
+		},
+		"GET /v1/docs": {
+			OperationId: "getApiDocs",
+			Summary:     "Swagger UI rendering this document",
+			Tags:        []string{tagDocs},
+			Responses: map[string]Response{
+				"200": {Description: "HTML page", Content: map[string]MediaType{"text/html": {Schema: &Schema{Type: "string"}}}},
+			},
+		},
+		"GET /v1/docs/{asset}": {
+			OperationId: "getApiDocsAsset",
+			Summary:     "Stylesheet or script of the Swagger UI page",
+			Tags:        []string{tagDocs},
+			Parameters: []Parameter{
+				{Name: "asset", In: "path", Description: "File name of the asset, e.g. swagger-ui-bundle.js", Required: true, Schema: stringSchema()},
+			},
+			Responses: map[string]Response{
+				"200": {Description: "CSS or JavaScript file", Content: map[string]MediaType{"text/css": {Schema: &Schema{Type: "string"}}, "text/javascript": {Schema: &Schema{Type: "string"}}}},
+				"404": errorResponse("No such asset"),
+			},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON schema of the OpenAPI document
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// Returns the schema of values of type t as encoded by encoding/json. Structs are added to the
+// components schemas under their type name and referenced.
+func schemaOf(t reflect.Type, components map[string]*Schema) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, ok := components[t.Name()]; !ok {
+			// Registered before the fields so that recursive types terminate
+			schema := object(map[string]*Schema{})
+			components[t.Name()] = schema
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				name, omitEmpty := jsonName(field)
+				if name == "" {
+					continue
+				}
+				schema.Properties[name] = schemaOf(field.Type, components)
+				if !omitEmpty {
+					schema.Required = append(schema.Required, name)
+				}
+			}
+		}
+		return ref(t.Name())
+	case reflect.Slice, reflect.Array:
+		return array(schemaOf(t.Elem(), components))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), components)}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
+
+// Returns the JSON name of an exported struct field and whether it is omitted when empty.
+// The name is empty for fields which are not encoded.
+func jsonName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitEmpty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/youtube-service/internal/entities"
+)
+
+// Number of videos a subscriber can lag behind before it is dropped
+const subscriptionBuffer = 256
+
+// Set as the error of subscriptions dropped because they did not keep up with published videos
+var ErrSlowSubscriber = errors.New("subscriber fell behind published videos")
+
+// Receives the videos published after it subscribed until it is closed
+type Subscription struct {
+	videos chan entities.Video
+	err    error
+}
+
+var (
+	mu            sync.Mutex
+	subscriptions = make(map[*Subscription]struct{})
+	handlers      []func([]entities.Video)
+)
+
+// Registers a handler called with the videos of every Publish before they are sent to subscribers,
+// for consumers which must not miss videos such as webhook deliveries. Handlers are called in the
+// order they were registered and block the publisher, so they should only enqueue work.
+func Handle(handler func([]entities.Video)) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = append(handlers, handler)
+}
+
+// Subscribes to the videos inserted into the database from now on. The subscription must be closed
+// when done.
+func Subscribe() *Subscription {
+	sub := &Subscription{videos: make(chan entities.Video, subscriptionBuffer)}
+	mu.Lock()
+	subscriptions[sub] = struct{}{}
+	mu.Unlock()
+	return sub
+}
+
+// Returns the channel of published videos, which is closed when the subscription is closed or dropped
+func (s *Subscription) Videos() <-chan entities.Video {
+	return s.videos
+}
+
+// Returns ErrSlowSubscriber once the videos channel was closed because the subscriber fell behind
+func (s *Subscription) Err() error {
+	mu.Lock()
+	defer mu.Unlock()
+	return s.err
+}
+
+// Unsubscribes and closes the videos channel. Closing twice is a no-op.
+func (s *Subscription) Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	unsubscribe(s, nil)
+}
+
+// Must be called with mu held
+func unsubscribe(s *Subscription, err error) {
+	if _, ok := subscriptions[s]; !ok {
+		return
+	}
+	delete(subscriptions, s)
+	s.err = err
+	close(s.videos)
+}
+
+// Passes the videos to the handlers, then sends them to every subscriber in order without blocking.
+// Subscribers whose buffer is full are dropped with ErrSlowSubscriber so that they can catch up from
+// the database.
+func Publish(videos []entities.Video) {
+	if len(videos) == 0 {
+		return
+	}
+	mu.Lock()
+	registered := handlers
+	mu.Unlock()
+	for _, handler := range registered {
+		handler(videos)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for sub := range subscriptions {
+		for _, video := range videos {
+			select {
+			case sub.videos <- video:
+			default:
+				unsubscribe(sub, ErrSlowSubscriber)
+			}
+			if sub.err != nil {
+				break
+			}
+		}
+	}
+}
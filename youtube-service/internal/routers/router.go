@@ -2,21 +2,193 @@ package routers
 
 import (
 	fiber "github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/youtube-service/internal/handlers"
 )
 
 // SetRoutes initializes middlewares and creates
 // routes for all the endpoints
 func SetRoutes(app *fiber.App) {
-	app.Get("/get_video", func(c *fiber.Ctx) error {
+	// Sets the X-Request-ID header which is also returned in error bodies
+	app.Use(requestid.New())
+
+	v1 := app.Group("/v1")
+	setV1Routes(v1)
+
+	// Feeds are also served without the version, as feed readers keep the URL they were subscribed to
+	setFeedRoutes(app)
+
+	// Verb style paths of the API before it was versioned, kept as aliases of their /v1 successors
+	app.Get("/get_video", deprecated("/v1/videos"), func(c *fiber.Ctx) error {
+		return get_video.Do(c)
+	})
+
+	app.Get("/search_video", deprecated("/v1/videos/search"), func(c *fiber.Ctx) error {
+		return search_video.Do(c)
+	})
+
+	app.Get("/trending", deprecated("/v1/videos/trending"), func(c *fiber.Ctx) error {
+		return trending.Do(c)
+	})
+
+	app.Get("/videos/:uniqueId", deprecated(""), func(c *fiber.Ctx) error {
+		return lookup_video.Do(c)
+	})
+
+	app.Post("/videos\\:batchGet", deprecated(""), func(c *fiber.Ctx) error {
+		return batch_get_videos.Do(c)
+	})
+
+	app.Get("/videos/:uniqueId/related", deprecated(""), func(c *fiber.Ctx) error {
+		return related_videos.Do(c)
+	})
+
+	app.Get("/suggest", deprecated("/v1/suggestions"), func(c *fiber.Ctx) error {
+		return suggest.Do(c)
+	})
+
+	app.Post("/add_key", deprecated("/v1/keys"), func(c *fiber.Ctx) error {
+		return add_key.Do(c)
+	})
+}
+
+// Creates the routes of version 1 of the API.
+// Static paths are registered before /videos/:uniqueId so that they are not taken for video ids.
+func setV1Routes(router fiber.Router) {
+	router.Get("/videos", func(c *fiber.Ctx) error {
 		return get_video.Do(c)
 	})
 
-	app.Get("/search_video", func(c *fiber.Ctx) error {
+	router.Get("/videos/search", func(c *fiber.Ctx) error {
 		return search_video.Do(c)
 	})
 
-	app.Post("/add_key", func(c *fiber.Ctx) error {
+	router.Get("/videos/trending", func(c *fiber.Ctx) error {
+		return trending.Do(c)
+	})
+
+	router.Get("/videos/stream", func(c *fiber.Ctx) error {
+		return stream_videos.Do(c)
+	})
+
+	router.Post("/videos\\:batchGet", func(c *fiber.Ctx) error {
+		return batch_get_videos.Do(c)
+	})
+
+	router.Get("/videos/:uniqueId", func(c *fiber.Ctx) error {
+		return lookup_video.Do(c)
+	})
+
+	router.Get("/videos/:uniqueId/related", func(c *fiber.Ctx) error {
+		return related_videos.Do(c)
+	})
+
+	router.Get("/suggestions", func(c *fiber.Ctx) error {
+		return suggest.Do(c)
+	})
+
+	setFeedRoutes(router)
+
+	router.Post("/keys", func(c *fiber.Ctx) error {
 		return add_key.Do(c)
 	})
+
+	// Webhooks can only be managed with the admin token
+	router.Post("/webhooks", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return create_webhook.Do(c)
+	})
+
+	router.Get("/webhooks", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return list_webhooks.Do(c)
+	})
+
+	router.Get("/webhooks/:webhookId", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return get_webhook.Do(c)
+	})
+
+	router.Patch("/webhooks/:webhookId", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return update_webhook.Do(c)
+	})
+
+	router.Delete("/webhooks/:webhookId", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return delete_webhook.Do(c)
+	})
+
+	router.Get("/webhooks/:webhookId/deliveries", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return list_webhook_deliveries.Do(c)
+	})
+
+	router.Post("/webhooks/:webhookId/deliveries/:deliveryId\\:redeliver", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return redeliver_webhook_delivery.Do(c)
+	})
+
+	// Alert rules can only be managed with the admin token
+	router.Post("/alerts", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return create_alert_rule.Do(c)
+	})
+
+	router.Get("/alerts", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return list_alert_rules.Do(c)
+	})
+
+	router.Get("/alerts/:ruleId", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return get_alert_rule.Do(c)
+	})
+
+	router.Delete("/alerts/:ruleId", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return delete_alert_rule.Do(c)
+	})
+
+	router.Post("/alerts/:ruleId\\:test", handlers.RequireAdmin, func(c *fiber.Ctx) error {
+		return test_alert_rule.Do(c)
+	})
+
+	router.Get("/graphql", func(c *fiber.Ctx) error {
+		return graphql.Do(c)
+	})
+
+	router.Post("/graphql", func(c *fiber.Ctx) error {
+		return graphql.Do(c)
+	})
+
+	router.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return openapi_document.Do(c)
+	})
+
+	router.Get("/docs", func(c *fiber.Ctx) error {
+		return api_docs.Do(c)
+	})
+
+	router.Get("/docs/:asset", func(c *fiber.Ctx) error {
+		return api_docs_asset.Do(c)
+	})
+}
+
+// Creates the routes of the feeds of the stored videos of a topic
+func setFeedRoutes(router fiber.Router) {
+	router.Get("/feeds/:topic.atom", func(c *fiber.Ctx) error {
+		return atom_feed.Do(c)
+	})
+
+	router.Get("/feeds/:topic.rss", func(c *fiber.Ctx) error {
+		return rss_feed.Do(c)
+	})
+
+	router.Get("/feeds/:topic.json", func(c *fiber.Ctx) error {
+		return json_feed.Do(c)
+	})
+}
+
+// Marks the responses of a deprecated path with the Deprecation header and links to the path
+// replacing it. An empty successor means the same path under /v1.
+func deprecated(successor string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := successor
+		if path == "" {
+			path = "/v1" + c.Path()
+		}
+		c.Set("Deprecation", "true")
+		c.Set(fiber.HeaderLink, "<"+path+">; rel=\"successor-version\"")
+		return c.Next()
+	}
 }
@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"testing"
+
+	fiber "github.com/gofiber/fiber/v2"
+	"github.com/youtube-service/internal/openapi"
+)
+
+// Every route must be described in the OpenAPI document of internal/openapi
+func TestRoutesAreDocumented(t *testing.T) {
+	app := fiber.New()
+	SetRoutes(app)
+
+	if undocumented := openapi.Undocumented(app); len(undocumented) > 0 {
+		t.Errorf("routes missing from the OpenAPI document in internal/openapi: %v", undocumented)
+	}
+}
@@ -0,0 +1,154 @@
+package highlight
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/kljensen/snowball"
+)
+
+const ellipsis = "…"
+
+// Terms are the stems of the words to highlight in a language
+type Terms struct {
+	// Snowball stemmer language, empty for languages without stemmer
+	language string
+	stems    map[string]bool
+}
+
+// Returns the stem of a word the same way the text index analyzes it, so that e.g. in english
+// "matches" highlights "match" and "matching". Words of languages without stemmer are only lowercased.
+func Stem(word string, language string) string {
+	word = strings.ToLower(word)
+	if language == "" {
+		return word
+	}
+	stem, err := snowball.Stem(word, language, true)
+	if err != nil {
+		return word
+	}
+	return stem
+}
+
+// Returns the terms of the words of the given phrases and words in the snowball stemmer language
+func NewTerms(language string, values ...string) Terms {
+	terms := Terms{language: language, stems: make(map[string]bool)}
+	terms.Add(values...)
+	return terms
+}
+
+// Adds the words of the given phrases and words to the terms
+func (t Terms) Add(values ...string) {
+	for _, value := range values {
+		for _, word := range words(value) {
+			t.stems[Stem(word.text, t.language)] = true
+		}
+	}
+}
+
+type word struct {
+	text       string
+	start, end int // rune offsets in the text
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)
+}
+
+func words(text string) []word {
+	runes := []rune(text)
+	result := make([]word, 0)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		result = append(result, word{text: string(runes[start:i]), start: start, end: i})
+	}
+	return result
+}
+
+// Returns the words of the text matching the terms
+func matches(text string, terms Terms) []word {
+	result := make([]word, 0)
+	for _, w := range words(text) {
+		if terms.stems[Stem(w.text, terms.language)] {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// Wraps the words of runes[from:to] matching the terms in the pre and post markers. The text is HTML
+// escaped so that the result can be rendered as HTML with the default markers, while the markers are not.
+func wrap(runes []rune, from, to int, found []word, pre, post string) string {
+	var b strings.Builder
+	pos := from
+	for _, w := range found {
+		if w.start < from || w.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:w.start])))
+		b.WriteString(pre)
+		b.WriteString(html.EscapeString(string(runes[w.start:w.end])))
+		b.WriteString(post)
+		pos = w.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	return b.String()
+}
+
+// Returns the whole HTML escaped text with the words matching the terms wrapped in the pre and post
+// markers and whether any word matched
+func Highlight(text string, terms Terms, pre, post string) (string, bool) {
+	found := matches(text, terms)
+	runes := []rune(text)
+	return wrap(runes, 0, len(runes), found, pre, post), len(found) > 0
+}
+
+// Returns an HTML escaped excerpt of at most length characters of the text, not counting the markers
+// and escapes, around the first word matching the terms with the matching words wrapped in the pre and
+// post markers, and whether any word matched. Without matches the beginning of the text is returned.
+func Snippet(text string, terms Terms, length int, pre, post string) (string, bool) {
+	found := matches(text, terms)
+	runes := []rune(text)
+	if len(runes) <= length {
+		return wrap(runes, 0, len(runes), found, pre, post), len(found) > 0
+	}
+
+	// Start a few words before the first match so that it has some context
+	from := 0
+	if len(found) > 0 {
+		from = found[0].start - length/4
+		if from < 0 {
+			from = 0
+		}
+	}
+	to := from + length
+	if to > len(runes) {
+		to = len(runes)
+		from = to - length
+	}
+
+	// Do not cut words at the edges of the snippet
+	for from > 0 && isWordRune(runes[from-1]) && from < to {
+		from++
+	}
+	for to < len(runes) && isWordRune(runes[to]) && to > from {
+		to--
+	}
+
+	snippet := strings.TrimSpace(wrap(runes, from, to, found, pre, post))
+	if from > 0 {
+		snippet = ellipsis + snippet
+	}
+	if to < len(runes) {
+		snippet += ellipsis
+	}
+	return snippet, len(found) > 0
+}
@@ -0,0 +1,77 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		terms   Terms
+		want    string
+		matched bool
+	}{
+		{"word", "India vs Australia", NewTerms("english", "india"), "<em>India</em> vs Australia", true},
+		{"stemmed", "Matching the match of matches", NewTerms("english", "matches"), "<em>Matching</em> the <em>match</em> of <em>matches</em>", true},
+		{"without stemmer", "Matching the match", NewTerms("", "match"), "Matching the <em>match</em>", true},
+		{"phrase", "World Cup final", NewTerms("english", "world cup"), "<em>World</em> <em>Cup</em> final", true},
+		{"no match", "World Cup final", NewTerms("english", "cricket"), "World Cup final", false},
+		{"multi-byte runes", "Café über alles", NewTerms("", "über"), "Café <em>über</em> alles", true},
+		{"escaped", `<script>alert("x")</script> & cricket`, NewTerms("english", "cricket"), "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; <em>cricket</em>", true},
+		{"escaped match", "<b>cricket</b>", NewTerms("english", "b"), "&lt;<em>b</em>&gt;cricket&lt;/<em>b</em>&gt;", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, matched := Highlight(test.text, test.terms, "<em>", "</em>")
+			if got != test.want || matched != test.matched {
+				t.Errorf("Highlight(%q) = %q, %v, want %q, %v", test.text, got, matched, test.want, test.matched)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "The first innings started late because of rain, then India scored quickly and won the match by six wickets in the end"
+	tests := []struct {
+		name    string
+		text    string
+		terms   Terms
+		length  int
+		want    string
+		matched bool
+	}{
+		{"shorter than the length", "India won", NewTerms("english", "india"), 160, "<em>India</em> won", true},
+		{"around the first match", text, NewTerms("english", "india"), 40, "…then <em>India</em> scored quickly and won…", true},
+		{"at the end", text, NewTerms("english", "end"), 40, "…won the match by six wickets in the <em>end</em>", true},
+		{"without match", text, NewTerms("english", "cricket"), 30, "The first innings started late…", false},
+		{"multi-byte runes", "Ça a été un très beau match à Zürich, über alles", NewTerms("", "zürich"), 20, "…à <em>Zürich</em>, über…", true},
+		{"escaped", "a long introduction with <b>&<i> cricket match and more words after it", NewTerms("english", "cricket"), 30, "…b&gt;&amp;&lt;i&gt; <em>cricket</em> match and more…", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, matched := Snippet(test.text, test.terms, test.length, "<em>", "</em>")
+			if got != test.want || matched != test.matched {
+				t.Errorf("Snippet(%q, %d) = %q, %v, want %q, %v", test.text, test.length, got, matched, test.want, test.matched)
+			}
+		})
+	}
+}
+
+// Snippets are never longer than the length, not counting the markers and ellipses
+func TestSnippetLength(t *testing.T) {
+	text := strings.Repeat("the cricket match of the day ", 20)
+	terms := NewTerms("english", "match")
+	for length := 10; length <= 100; length += 7 {
+		snippet, _ := Snippet(text, terms, length, "<em>", "</em>")
+		snippet = strings.NewReplacer("<em>", "", "</em>", "", ellipsis, "").Replace(snippet)
+		if n := utf8.RuneCountInString(snippet); n > length {
+			t.Errorf("Snippet of length %d has %d characters: %q", length, n, snippet)
+		}
+		if !strings.Contains(text, snippet) {
+			t.Errorf("Snippet of length %d %q is not an excerpt of the text", length, snippet)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// Default language of the text index, used when the language of a document is unknown
+	Default = "en"
+	// None disables stemming and stop words in mongo text search. Used for languages it does not support.
+	None = "none"
+)
+
+// Languages supported by mongo text search, keyed by ISO 639-1 code
+var textSearchLanguages = map[string]bool{
+	"da": true, "nl": true, "en": true, "fi": true, "fr": true, "de": true, "hu": true, "it": true,
+	"nb": true, "pt": true, "ro": true, "ru": true, "es": true, "sv": true, "tr": true,
+}
+
+// Snowball stemmers used for highlighting, keyed by ISO 639-1 code
+var stemmerLanguages = map[string]string{
+	"en": "english",
+	"es": "spanish",
+	"fr": "french",
+	"ru": "russian",
+	"sv": "swedish",
+	"nb": "norwegian",
+	"hu": "hungarian",
+}
+
+// Languages guessed from the script of a text. Latin is not listed as it is shared by too many languages.
+var scriptLanguages = []struct {
+	script   *unicode.RangeTable
+	language string
+}{
+	{unicode.Devanagari, "hi"},
+	{unicode.Bengali, "bn"},
+	{unicode.Gurmukhi, "pa"},
+	{unicode.Gujarati, "gu"},
+	{unicode.Tamil, "ta"},
+	{unicode.Telugu, "te"},
+	{unicode.Kannada, "kn"},
+	{unicode.Malayalam, "ml"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Thai, "th"},
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+}
+
+// Returns the ISO 639-1 code of a BCP 47 language tag, e.g. pt for pt-BR
+func Base(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	// YouTube uses the macro language code for Norwegian
+	if tag == "no" || tag == "nn" {
+		return "nb"
+	}
+	return tag
+}
+
+// Guesses the language of a text from the script most of its letters are written in.
+// Returns an empty string for Latin script text, whose language cannot be told from the script.
+func Detect(text string) string {
+	counts := make(map[string]int)
+	latin := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.script, r) {
+				counts[s.language]++
+				break
+			}
+		}
+	}
+
+	detected, max := "", latin
+	for language, count := range counts {
+		if count > max {
+			detected, max = language, count
+		}
+	}
+	// Japanese text mixes kana with Han characters
+	if detected == "zh" && counts["ja"] > 0 {
+		detected = "ja"
+	}
+	return detected
+}
+
+// Returns the language to store as the mongo text search language override of a document
+// or to pass as $language of a text search. Languages mongo does not support, such as Hindi,
+// are indexed without stemming so that documents in them can still be inserted and searched.
+// Returns an empty string for an empty tag, in which case the default language is used.
+func TextSearchLanguage(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	if strings.EqualFold(tag, None) {
+		return None
+	}
+	if base := Base(tag); textSearchLanguages[base] {
+		return base
+	}
+	return None
+}
+
+// Returns the snowball stemmer language of a language tag, or an empty string if there is none
+func StemmerLanguage(tag string) string {
+	return stemmerLanguages[Base(tag)]
+}
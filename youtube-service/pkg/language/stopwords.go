@@ -0,0 +1,43 @@
+package language
+
+import "strings"
+
+// Stop words of the languages videos are most often described in, keyed by ISO 639-1 code. Words of
+// links, which descriptions are full of, are stop words in every language.
+var stopWords = map[string]map[string]bool{
+	"": words("http https www com org net html youtube youtu watch"),
+	"en": words(`a about above after again against all am an and any are as at be because been before
+		being below between both but by can could did do does doing down during each few for from
+		further had has have having he her here hers herself him himself his how i if in into is it
+		its itself just let me more most my myself no nor not now of off on once only or other our
+		ours ourselves out over own same she should so some such than that the their theirs them
+		themselves then there these they this those through to too under until up very was we were
+		what when where which while who whom why will with would you your yours yourself yourselves
+		also get got like new one video videos watch subscribe channel`),
+	"es": words(`a al algo ante como con contra cual cuando de del desde donde durante e el ella ellas
+		ellos en entre era es esa ese eso esta este esto estos fue ha hay la las le les lo los mas mi
+		más muy no nos o os para pero por que se sin sobre su sus también te tu un una uno unos y ya`),
+	"fr": words(`a au aux avec ce ces cette dans de des du elle en est et eu il ils je la le les leur
+		lui ma mais me mes mon ne nos notre nous on ou par pas pour qu que qui sa se ses son sont sur
+		ta te tes toi ton tu un une vos votre vous y été être`),
+	"de": words(`aber als am an auch auf aus bei bin bis da das dass dem den der des die doch du ein
+		eine einem einen einer eines er es für hat ich ihr im in ist ja kein mit nach nicht noch nur
+		oder sich sie sind so um und uns von vor war was wie wir zu zum zur`),
+	"pt": words(`a ao aos as com como da das de do dos e ela ele em entre era essa esse esta este eu foi
+		há isso mais mas me meu minha na nas não no nos o os ou para pela pelo por que se sem seu sua
+		são também te um uma você`),
+}
+
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(list) {
+		set[word] = true
+	}
+	return set
+}
+
+// Reports whether the lowercase word is a stop word of the language of the tag, or a stop word of
+// every language. Words of languages without a stop word list are only checked against the latter.
+func IsStopWord(tag string, word string) bool {
+	return stopWords[""][word] || stopWords[Base(tag)][word]
+}
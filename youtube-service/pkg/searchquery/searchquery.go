@@ -0,0 +1,219 @@
+package searchquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldChannel     = "channel"
+)
+
+const orOperator = "OR"
+
+var fields = map[string]bool{
+	FieldTitle:       true,
+	FieldDescription: true,
+	FieldChannel:     true,
+}
+
+// Term is a word or a quoted phrase of a search query, optionally negated
+// and scoped to a field. Field is empty for terms searching all fields.
+type Term struct {
+	Field   string
+	Value   string
+	Phrase  bool
+	Negated bool
+}
+
+// Query is a parsed search query. Terms joined by OR are in the same group.
+// Plain words are ranking terms of which any may match, like in a text search,
+// while phrases, field scoped terms and OR groups must match and negated terms must not.
+type Query struct {
+	Groups [][]Term
+	// Language in which the query is analyzed, empty for the default language of the index
+	Language string
+}
+
+// SyntaxError is returned for malformed queries
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("malformed query at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parses a search query supporting quoted phrases, negation with -, OR between terms
+// and title:, description: and channel: field prefixes, e.g. "ind vs aus" -highlights title:live
+func Parse(s string) (Query, error) {
+	var q Query
+	runes := []rune(s)
+	pendingOr := false
+	orPos := 0
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		term, next, err := parseTerm(runes, i)
+		if err != nil {
+			return q, err
+		}
+		i = next
+
+		if !term.Phrase && !term.Negated && term.Field == "" && term.Value == orOperator {
+			if len(q.Groups) == 0 || pendingOr {
+				return q, &SyntaxError{Pos: start, Msg: "OR must be between two terms"}
+			}
+			if groupNegated(q.Groups[len(q.Groups)-1]) {
+				return q, &SyntaxError{Pos: start, Msg: "negated terms cannot be combined with OR"}
+			}
+			pendingOr = true
+			orPos = start
+			continue
+		}
+
+		if pendingOr {
+			if term.Negated {
+				return q, &SyntaxError{Pos: start, Msg: "negated terms cannot be combined with OR"}
+			}
+			last := len(q.Groups) - 1
+			q.Groups[last] = append(q.Groups[last], term)
+			pendingOr = false
+			continue
+		}
+		q.Groups = append(q.Groups, []Term{term})
+	}
+
+	if pendingOr {
+		return q, &SyntaxError{Pos: orPos, Msg: "OR must be between two terms"}
+	}
+	if len(q.Groups) == 0 {
+		return q, &SyntaxError{Pos: 0, Msg: "query is empty"}
+	}
+	for _, group := range q.Groups {
+		if !groupNegated(group) {
+			return q, nil
+		}
+	}
+	return q, &SyntaxError{Pos: 0, Msg: "query must contain at least one term that is not negated"}
+}
+
+// Parses the term starting at runes[i] and returns it with the position following it
+func parseTerm(runes []rune, i int) (Term, int, error) {
+	var term Term
+	start := i
+
+	if runes[i] == '-' {
+		term.Negated = true
+		i++
+		if i == len(runes) || unicode.IsSpace(runes[i]) {
+			return term, i, &SyntaxError{Pos: start, Msg: "- must be followed by a term"}
+		}
+	}
+
+	// A field prefix is only recognised for known fields, so words like http://... are kept as is
+	if colon := indexRune(runes[i:], ':'); colon > 0 {
+		field := strings.ToLower(string(runes[i : i+colon]))
+		if fields[field] {
+			term.Field = field
+			i += colon + 1
+			if i == len(runes) || unicode.IsSpace(runes[i]) {
+				return term, i, &SyntaxError{Pos: start, Msg: fmt.Sprintf("%s: must be followed by a term", field)}
+			}
+		}
+	}
+
+	if runes[i] == '"' {
+		end := indexRune(runes[i+1:], '"')
+		if end < 0 {
+			return term, i, &SyntaxError{Pos: i, Msg: "unterminated quoted phrase"}
+		}
+		term.Phrase = true
+		term.Value = strings.Join(strings.Fields(string(runes[i+1:i+1+end])), " ")
+		if term.Value == "" {
+			return term, i, &SyntaxError{Pos: i, Msg: "quoted phrase is empty"}
+		}
+		next := i + end + 2
+		if next < len(runes) && !unicode.IsSpace(runes[next]) {
+			return term, next, &SyntaxError{Pos: next, Msg: "quoted phrase must be separated from the next term by a space"}
+		}
+		return term, next, nil
+	}
+
+	end := i
+	for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+		end++
+	}
+	if end < len(runes) && runes[end] == '"' {
+		return term, end, &SyntaxError{Pos: end, Msg: "quoted phrase must be separated from the previous term by a space"}
+	}
+	term.Value = string(runes[i:end])
+	return term, end, nil
+}
+
+func indexRune(runes []rune, r rune) int {
+	for i, c := range runes {
+		if c == r {
+			return i
+		}
+		if unicode.IsSpace(c) && r != '"' {
+			return -1
+		}
+	}
+	return -1
+}
+
+func groupNegated(group []Term) bool {
+	return len(group) == 1 && group[0].Negated
+}
+
+// Returns the terms of the query that are not negated
+func (q Query) PositiveTerms() []Term {
+	terms := make([]Term, 0)
+	for _, group := range q.Groups {
+		for _, term := range group {
+			if !term.Negated {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+func (t Term) String() string {
+	var b strings.Builder
+	if t.Negated {
+		b.WriteString("-")
+	}
+	if t.Field != "" {
+		b.WriteString(t.Field + ":")
+	}
+	if t.Phrase {
+		b.WriteString(`"` + t.Value + `"`)
+	} else {
+		b.WriteString(t.Value)
+	}
+	return b.String()
+}
+
+// Returns the normalised query
+func (q Query) String() string {
+	groups := make([]string, 0, len(q.Groups))
+	for _, group := range q.Groups {
+		terms := make([]string, 0, len(group))
+		for _, term := range group {
+			terms = append(terms, term.String())
+		}
+		groups = append(groups, strings.Join(terms, " "+orOperator+" "))
+	}
+	return strings.Join(groups, " ")
+}
@@ -0,0 +1,124 @@
+package searchquery
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query  string
+		groups [][]Term
+	}{
+		{"cricket", [][]Term{{{Value: "cricket"}}}},
+		{"  ind   vs aus ", [][]Term{{{Value: "ind"}}, {{Value: "vs"}}, {{Value: "aus"}}}},
+		{`"ind vs aus"`, [][]Term{{{Value: "ind vs aus", Phrase: true}}}},
+		{`"  ind   vs aus "`, [][]Term{{{Value: "ind vs aus", Phrase: true}}}},
+		{"cricket -highlights", [][]Term{{{Value: "cricket"}}, {{Value: "highlights", Negated: true}}}},
+		{`cricket -"full match"`, [][]Term{{{Value: "cricket"}}, {{Value: "full match", Phrase: true, Negated: true}}}},
+		{"title:live", [][]Term{{{Field: FieldTitle, Value: "live"}}}},
+		{"TITLE:live", [][]Term{{{Field: FieldTitle, Value: "live"}}}},
+		{`description:"world cup"`, [][]Term{{{Field: FieldDescription, Value: "world cup", Phrase: true}}}},
+		{"cricket -channel:UCabc", [][]Term{{{Value: "cricket"}}, {{Field: FieldChannel, Value: "UCabc", Negated: true}}}},
+		{"tags:live", [][]Term{{{Value: "tags:live"}}}},
+		{"https://youtu.be/x", [][]Term{{{Value: "https://youtu.be/x"}}}},
+		{"t20 OR odi", [][]Term{{{Value: "t20"}, {Value: "odi"}}}},
+		{`t20 OR "one day" OR title:test`, [][]Term{{{Value: "t20"}, {Value: "one day", Phrase: true}, {Field: FieldTitle, Value: "test"}}}},
+		{"t20 or odi", [][]Term{{{Value: "t20"}}, {{Value: "or"}}, {{Value: "odi"}}}},
+		{`"ind vs aus" -highlights title:live`, [][]Term{
+			{{Value: "ind vs aus", Phrase: true}},
+			{{Value: "highlights", Negated: true}},
+			{{Field: FieldTitle, Value: "live"}},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error %v", test.query, err)
+			}
+			if !reflect.DeepEqual(q.Groups, test.groups) {
+				t.Errorf("Parse(%q) = %+v, want %+v", test.query, q.Groups, test.groups)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"-highlights", 0},
+		{"-a -b", 0},
+		{"cricket -", 8},
+		{"cricket - highlights", 8},
+		{"title:", 0},
+		{"cricket title: live", 8},
+		{`"ind vs aus`, 0},
+		{`cricket "`, 8},
+		{`""`, 0},
+		{`"  "`, 0},
+		{`a"b"`, 1},
+		{`"a"b`, 3},
+		{`title:"a""b"`, 9},
+		{"OR cricket", 0},
+		{"cricket OR", 8},
+		{"t20 OR OR odi", 7},
+		{"cricket OR -highlights", 11},
+		{"-highlights OR cricket", 12},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			_, err := Parse(test.query)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) returned %v, want a *SyntaxError", test.query, err)
+			}
+			if syntaxErr.Pos != test.pos {
+				t.Errorf("Parse(%q) failed at position %d, want %d: %v", test.query, syntaxErr.Pos, test.pos, err)
+			}
+		})
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"cricket", "cricket"},
+		{`  "ind   vs aus"   -highlights  TITLE:live `, `"ind vs aus" -highlights title:live`},
+		{`t20 OR "one day" cricket`, `t20 OR "one day" cricket`},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error %v", test.query, err)
+			}
+			if got := q.String(); got != test.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", test.query, got, test.want)
+			}
+			// The normalised query parses to the same query
+			again, err := Parse(q.String())
+			if err != nil || !reflect.DeepEqual(again.Groups, q.Groups) {
+				t.Errorf("Parse(%q) = %+v, %v, want %+v", q.String(), again.Groups, err, q.Groups)
+			}
+		})
+	}
+}
+
+func TestPositiveTerms(t *testing.T) {
+	q, err := Parse(`"ind vs aus" -highlights t20 OR odi`)
+	if err != nil {
+		t.Fatalf("Parse returned error %v", err)
+	}
+	want := []Term{{Value: "ind vs aus", Phrase: true}, {Value: "t20"}, {Value: "odi"}}
+	if got := q.PositiveTerms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PositiveTerms() = %+v, want %+v", got, want)
+	}
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 
 	log "github.com/sirupsen/logrus"
@@ -21,3 +23,42 @@ func GetEnvInt(key string, defaultVal int64) int64 {
 	}
 	return int64(v)
 }
+
+// Converts string environment variable to float64. Returns default value if not found or not a number
+func GetEnvFloat(key string, defaultVal float64) float64 {
+	s := os.Getenv(key)
+	if s == "" {
+		log.Errorf("Utils: environment variable %v not found, using default value %v.", key, defaultVal)
+		return defaultVal
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		log.Errorf("Utils: environment variable %v is not a valid number: %q, using default value %v.", key, s, defaultVal)
+		return defaultVal
+	}
+	return v
+}
+
+var iso8601DurationRegex = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Converts an ISO 8601 duration as returned by the YouTube API (e.g. PT1H2M3S) to seconds
+func ParseISO8601Duration(s string) (int64, error) {
+	matches := iso8601DurationRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+
+	var seconds int64
+	units := []int64{24 * 60 * 60, 60 * 60, 60, 1}
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(matches[i+1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+		seconds += v * unit
+	}
+	return seconds, nil
+}
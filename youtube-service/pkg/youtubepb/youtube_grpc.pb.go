@@ -0,0 +1,447 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: youtube/v1/youtube.proto
+
+package youtubepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// VideoServiceClient is the client API for VideoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VideoServiceClient interface {
+	// Stored videos, latest published first by default.
+	ListVideos(ctx context.Context, in *ListVideosRequest, opts ...grpc.CallOption) (*ListVideosResponse, error)
+	// Stored videos matching a search query, most relevant first by default.
+	SearchVideos(ctx context.Context, in *SearchVideosRequest, opts ...grpc.CallOption) (*SearchVideosResponse, error)
+	// The stored video with the given YouTube id, NOT_FOUND when it isn't stored.
+	GetVideo(ctx context.Context, in *GetVideoRequest, opts ...grpc.CallOption) (*Video, error)
+	// Streams videos as they are inserted by the job fetching videos from YouTube.
+	WatchNewVideos(ctx context.Context, in *WatchNewVideosRequest, opts ...grpc.CallOption) (VideoService_WatchNewVideosClient, error)
+}
+
+type videoServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVideoServiceClient(cc grpc.ClientConnInterface) VideoServiceClient {
+	return &videoServiceClient{cc}
+}
+
+func (c *videoServiceClient) ListVideos(ctx context.Context, in *ListVideosRequest, opts ...grpc.CallOption) (*ListVideosResponse, error) {
+	out := new(ListVideosResponse)
+	err := c.cc.Invoke(ctx, "/youtube.v1.VideoService/ListVideos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) SearchVideos(ctx context.Context, in *SearchVideosRequest, opts ...grpc.CallOption) (*SearchVideosResponse, error) {
+	out := new(SearchVideosResponse)
+	err := c.cc.Invoke(ctx, "/youtube.v1.VideoService/SearchVideos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) GetVideo(ctx context.Context, in *GetVideoRequest, opts ...grpc.CallOption) (*Video, error) {
+	out := new(Video)
+	err := c.cc.Invoke(ctx, "/youtube.v1.VideoService/GetVideo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) WatchNewVideos(ctx context.Context, in *WatchNewVideosRequest, opts ...grpc.CallOption) (VideoService_WatchNewVideosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VideoService_ServiceDesc.Streams[0], "/youtube.v1.VideoService/WatchNewVideos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &videoServiceWatchNewVideosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type VideoService_WatchNewVideosClient interface {
+	Recv() (*Video, error)
+	grpc.ClientStream
+}
+
+type videoServiceWatchNewVideosClient struct {
+	grpc.ClientStream
+}
+
+func (x *videoServiceWatchNewVideosClient) Recv() (*Video, error) {
+	m := new(Video)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// VideoServiceServer is the server API for VideoService service.
+// All implementations must embed UnimplementedVideoServiceServer
+// for forward compatibility
+type VideoServiceServer interface {
+	// Stored videos, latest published first by default.
+	ListVideos(context.Context, *ListVideosRequest) (*ListVideosResponse, error)
+	// Stored videos matching a search query, most relevant first by default.
+	SearchVideos(context.Context, *SearchVideosRequest) (*SearchVideosResponse, error)
+	// The stored video with the given YouTube id, NOT_FOUND when it isn't stored.
+	GetVideo(context.Context, *GetVideoRequest) (*Video, error)
+	// Streams videos as they are inserted by the job fetching videos from YouTube.
+	WatchNewVideos(*WatchNewVideosRequest, VideoService_WatchNewVideosServer) error
+	mustEmbedUnimplementedVideoServiceServer()
+}
+
+// UnimplementedVideoServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedVideoServiceServer struct {
+}
+
+func (UnimplementedVideoServiceServer) ListVideos(context.Context, *ListVideosRequest) (*ListVideosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVideos not implemented")
+}
+func (UnimplementedVideoServiceServer) SearchVideos(context.Context, *SearchVideosRequest) (*SearchVideosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchVideos not implemented")
+}
+func (UnimplementedVideoServiceServer) GetVideo(context.Context, *GetVideoRequest) (*Video, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVideo not implemented")
+}
+func (UnimplementedVideoServiceServer) WatchNewVideos(*WatchNewVideosRequest, VideoService_WatchNewVideosServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchNewVideos not implemented")
+}
+func (UnimplementedVideoServiceServer) mustEmbedUnimplementedVideoServiceServer() {}
+
+// UnsafeVideoServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VideoServiceServer will
+// result in compilation errors.
+type UnsafeVideoServiceServer interface {
+	mustEmbedUnimplementedVideoServiceServer()
+}
+
+func RegisterVideoServiceServer(s grpc.ServiceRegistrar, srv VideoServiceServer) {
+	s.RegisterService(&VideoService_ServiceDesc, srv)
+}
+
+func _VideoService_ListVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVideosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).ListVideos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.VideoService/ListVideos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).ListVideos(ctx, req.(*ListVideosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_SearchVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchVideosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).SearchVideos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.VideoService/SearchVideos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).SearchVideos(ctx, req.(*SearchVideosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_GetVideo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVideoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).GetVideo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.VideoService/GetVideo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).GetVideo(ctx, req.(*GetVideoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VideoService_WatchNewVideos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchNewVideosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VideoServiceServer).WatchNewVideos(m, &videoServiceWatchNewVideosServer{stream})
+}
+
+type VideoService_WatchNewVideosServer interface {
+	Send(*Video) error
+	grpc.ServerStream
+}
+
+type videoServiceWatchNewVideosServer struct {
+	grpc.ServerStream
+}
+
+func (x *videoServiceWatchNewVideosServer) Send(m *Video) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// VideoService_ServiceDesc is the grpc.ServiceDesc for VideoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VideoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "youtube.v1.VideoService",
+	HandlerType: (*VideoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListVideos",
+			Handler:    _VideoService_ListVideos_Handler,
+		},
+		{
+			MethodName: "SearchVideos",
+			Handler:    _VideoService_SearchVideos_Handler,
+		},
+		{
+			MethodName: "GetVideo",
+			Handler:    _VideoService_GetVideo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchNewVideos",
+			Handler:       _VideoService_WatchNewVideos_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "youtube/v1/youtube.proto",
+}
+
+// KeyAdminServiceClient is the client API for KeyAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KeyAdminServiceClient interface {
+	ListApiKeys(ctx context.Context, in *ListApiKeysRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error)
+	// Adds a key after checking it with the YouTube API.
+	AddApiKey(ctx context.Context, in *AddApiKeyRequest, opts ...grpc.CallOption) (*ApiKey, error)
+	// Expired keys are not used until they are checked again.
+	SetApiKeyExpired(ctx context.Context, in *SetApiKeyExpiredRequest, opts ...grpc.CallOption) (*ApiKey, error)
+	DeleteApiKey(ctx context.Context, in *DeleteApiKeyRequest, opts ...grpc.CallOption) (*DeleteApiKeyResponse, error)
+}
+
+type keyAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeyAdminServiceClient(cc grpc.ClientConnInterface) KeyAdminServiceClient {
+	return &keyAdminServiceClient{cc}
+}
+
+func (c *keyAdminServiceClient) ListApiKeys(ctx context.Context, in *ListApiKeysRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error) {
+	out := new(ListApiKeysResponse)
+	err := c.cc.Invoke(ctx, "/youtube.v1.KeyAdminService/ListApiKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyAdminServiceClient) AddApiKey(ctx context.Context, in *AddApiKeyRequest, opts ...grpc.CallOption) (*ApiKey, error) {
+	out := new(ApiKey)
+	err := c.cc.Invoke(ctx, "/youtube.v1.KeyAdminService/AddApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyAdminServiceClient) SetApiKeyExpired(ctx context.Context, in *SetApiKeyExpiredRequest, opts ...grpc.CallOption) (*ApiKey, error) {
+	out := new(ApiKey)
+	err := c.cc.Invoke(ctx, "/youtube.v1.KeyAdminService/SetApiKeyExpired", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyAdminServiceClient) DeleteApiKey(ctx context.Context, in *DeleteApiKeyRequest, opts ...grpc.CallOption) (*DeleteApiKeyResponse, error) {
+	out := new(DeleteApiKeyResponse)
+	err := c.cc.Invoke(ctx, "/youtube.v1.KeyAdminService/DeleteApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeyAdminServiceServer is the server API for KeyAdminService service.
+// All implementations must embed UnimplementedKeyAdminServiceServer
+// for forward compatibility
+type KeyAdminServiceServer interface {
+	ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error)
+	// Adds a key after checking it with the YouTube API.
+	AddApiKey(context.Context, *AddApiKeyRequest) (*ApiKey, error)
+	// Expired keys are not used until they are checked again.
+	SetApiKeyExpired(context.Context, *SetApiKeyExpiredRequest) (*ApiKey, error)
+	DeleteApiKey(context.Context, *DeleteApiKeyRequest) (*DeleteApiKeyResponse, error)
+	mustEmbedUnimplementedKeyAdminServiceServer()
+}
+
+// UnimplementedKeyAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedKeyAdminServiceServer struct {
+}
+
+func (UnimplementedKeyAdminServiceServer) ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
+}
+func (UnimplementedKeyAdminServiceServer) AddApiKey(context.Context, *AddApiKeyRequest) (*ApiKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddApiKey not implemented")
+}
+func (UnimplementedKeyAdminServiceServer) SetApiKeyExpired(context.Context, *SetApiKeyExpiredRequest) (*ApiKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetApiKeyExpired not implemented")
+}
+func (UnimplementedKeyAdminServiceServer) DeleteApiKey(context.Context, *DeleteApiKeyRequest) (*DeleteApiKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApiKey not implemented")
+}
+func (UnimplementedKeyAdminServiceServer) mustEmbedUnimplementedKeyAdminServiceServer() {}
+
+// UnsafeKeyAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeyAdminServiceServer will
+// result in compilation errors.
+type UnsafeKeyAdminServiceServer interface {
+	mustEmbedUnimplementedKeyAdminServiceServer()
+}
+
+func RegisterKeyAdminServiceServer(s grpc.ServiceRegistrar, srv KeyAdminServiceServer) {
+	s.RegisterService(&KeyAdminService_ServiceDesc, srv)
+}
+
+func _KeyAdminService_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListApiKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyAdminServiceServer).ListApiKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.KeyAdminService/ListApiKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyAdminServiceServer).ListApiKeys(ctx, req.(*ListApiKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyAdminService_AddApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyAdminServiceServer).AddApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.KeyAdminService/AddApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyAdminServiceServer).AddApiKey(ctx, req.(*AddApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyAdminService_SetApiKeyExpired_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetApiKeyExpiredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyAdminServiceServer).SetApiKeyExpired(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.KeyAdminService/SetApiKeyExpired",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyAdminServiceServer).SetApiKeyExpired(ctx, req.(*SetApiKeyExpiredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyAdminService_DeleteApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyAdminServiceServer).DeleteApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youtube.v1.KeyAdminService/DeleteApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyAdminServiceServer).DeleteApiKey(ctx, req.(*DeleteApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KeyAdminService_ServiceDesc is the grpc.ServiceDesc for KeyAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KeyAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "youtube.v1.KeyAdminService",
+	HandlerType: (*KeyAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListApiKeys",
+			Handler:    _KeyAdminService_ListApiKeys_Handler,
+		},
+		{
+			MethodName: "AddApiKey",
+			Handler:    _KeyAdminService_AddApiKey_Handler,
+		},
+		{
+			MethodName: "SetApiKeyExpired",
+			Handler:    _KeyAdminService_SetApiKeyExpired_Handler,
+		},
+		{
+			MethodName: "DeleteApiKey",
+			Handler:    _KeyAdminService_DeleteApiKey_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "youtube/v1/youtube.proto",
+}